@@ -96,12 +96,10 @@ func analyzeDescenderProperties(fontData FontData, scaleFactor float64) map[stri
 
 // findCommonBaseline determines the baseline position by analyzing lowercase letters without descenders
 func findCommonBaseline(fontData FontData, scaleFactor float64) int {
-	// Sample lowercase letters that typically don't have descenders
-	sampleChars := []string{"a", "e", "o", "x", "n", "m", "s", "c"}
-
 	var baselinePositions []int
 
-	for _, charStr := range sampleChars {
+	// Sample lowercase letters that typically don't have descenders
+	for _, charStr := range baselineSampleChars {
 		if bitmapLines, ok := fontData.Characters[charStr]; ok {
 			scaledLines := scaleCharacter(bitmapLines, scaleFactor)
 
@@ -0,0 +1,143 @@
+package ansifonts
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Animation selects one of the built-in animations of RenderAnimation
+type Animation int
+
+const (
+	GradientRotation Animation = iota // The gradient turns a full circle around the text
+	TypewriterReveal                  // The characters appear one at a time
+	ShadowSweep                       // A shadow circles around the text
+)
+
+// DefaultAnimationFrames is the frame count RenderAnimation uses when frames is zero
+const DefaultAnimationFrames = 24
+
+// shadowSweepReach is how far the swept shadow moves from the text in cells.
+// Cells are twice as tall as they are wide, so it moves half as far vertically.
+const shadowSweepReach = 2
+
+// animationNames maps the names accepted by ParseAnimation to animations
+var animationNames = map[string]Animation{
+	"gradient":   GradientRotation,
+	"typewriter": TypewriterReveal,
+	"shadow":     ShadowSweep,
+}
+
+// ParseAnimation returns the animation called name: "gradient", "typewriter" or "shadow"
+func ParseAnimation(name string) (Animation, error) {
+	animation, ok := animationNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return 0, fmt.Errorf("unknown animation %q (use gradient, typewriter or shadow)", name)
+	}
+	return animation, nil
+}
+
+// RenderAnimation renders the frames of a built-in animation of text.
+//
+// GradientRotation turns the gradient from TextColor to GradientColor a full
+// circle in frames steps; without a gradient it runs toward the text color
+// darkened. ShadowSweep moves a shadow of the glyphs, in the text color
+// darkened, around the text in frames steps, replacing the built-in shadow and
+// extrusion. Both keep every frame the same size. TypewriterReveal ignores
+// frames and renders one frame per visible character, each showing the text up
+// to that character.
+func RenderAnimation(text string, font *Font, options RenderOptions, animation Animation, frames int) [][]string {
+	if frames <= 0 {
+		frames = DefaultAnimationFrames
+	}
+
+	var result [][]string
+	switch animation {
+	case GradientRotation:
+		from, to := options.TextColor, options.GradientColor
+		if !options.UseGradient || to == "" || to == from {
+			to = blendHex(from, "#000000", 0.75)
+		}
+		options.UseGradient = false
+		customEffects := options.Effects
+		for i := range frames {
+			angle := 2 * math.Pi * float64(i) / float64(frames)
+			options.Effects = append(slices.Clone(customEffects), angleGradientEffect{from: from, to: to, angle: angle})
+			result = append(result, RenderTextWithOptions(text, font, options))
+		}
+	case TypewriterReveal:
+		runes := []rune(text)
+		for i, r := range runes {
+			if !unicode.IsSpace(r) {
+				result = append(result, RenderTextWithOptions(string(runes[:i+1]), font, options))
+			}
+		}
+	case ShadowSweep:
+		options.ShadowEnabled = false
+		options.ExtrusionEnabled = false
+		customEffects := options.Effects
+		color := blendHex(options.TextColor, "#000000", 0.6)
+		margin := marginEffect{x: shadowSweepReach, y: (shadowSweepReach + 1) / 2}
+		for i := range frames {
+			angle := 2 * math.Pi * float64(i) / float64(frames)
+			shadow := ShadowEffect{
+				X:     int(math.Round(shadowSweepReach * math.Cos(angle))),
+				Y:     int(math.Round(shadowSweepReach / 2 * math.Sin(angle))),
+				Color: color,
+			}
+			// The shadow goes first so custom effects paint over it
+			options.Effects = append([]Effect{margin, shadow}, customEffects...)
+			result = append(result, RenderTextWithOptions(text, font, options))
+		}
+	}
+	return result
+}
+
+// angleGradientEffect colors the cells without a color of their own with a
+// gradient running across the canvas at an angle, measured in radians
+// clockwise from left to right
+type angleGradientEffect struct {
+	from, to string
+	angle    float64
+}
+
+func (e angleGradientEffect) Bounds(width, height int) Rect {
+	return Rect{Width: width, Height: height}
+}
+
+func (e angleGradientEffect) Apply(canvas *Canvas) {
+	// Work in half-cell units so a cell is as tall as it looks
+	cos, sin := math.Cos(e.angle), math.Sin(e.angle)
+	width, height := float64(canvas.Width()), float64(canvas.Height())*2
+	extent := (width*math.Abs(cos) + height*math.Abs(sin)) / 2
+	if extent == 0 {
+		return
+	}
+
+	for y, row := range canvas.Cells {
+		for x, cell := range row {
+			if cell.Char == ' ' || cell.Color != "" {
+				continue
+			}
+			dx := float64(x) + 0.5 - width/2
+			dy := float64(y*2) + 1 - height/2
+			factor := 0.5 + (dx*cos+dy*sin)/(2*extent)
+			row[x].Color = blendHex(e.from, e.to, factor)
+		}
+	}
+}
+
+// marginEffect paints nothing but reserves x columns and y rows of canvas
+// around the text block, so frames with a moving effect keep one size
+type marginEffect struct {
+	x, y int
+}
+
+func (e marginEffect) Bounds(width, height int) Rect {
+	return Rect{X: -e.x, Y: -e.y, Width: width + 2*e.x, Height: height + 2*e.y}
+}
+
+func (marginEffect) Apply(*Canvas) {}
@@ -0,0 +1,413 @@
+package ansifonts
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// TextRun is a piece of text with its own font and options, laid out next to
+// other runs by ComposeRuns
+type TextRun struct {
+	Text    string         // Run text, which may contain newlines
+	Font    *Font          // Font of the run (nil uses the composition font)
+	Options *RenderOptions // Glyph options of the run (nil uses the composition options)
+	Kerning map[int]int    // Inline kerning adjustments keyed by rune index in Text
+}
+
+// composeItem is one rendered word or run of spaces of a composed line
+type composeItem struct {
+	plain    []string      // Unstyled rows
+	sources  sourceMap     // Palette slot behind every cell of plain, nil without a palette
+	options  RenderOptions // Glyph options the word is styled with
+	baseline int           // Row of the baseline within plain
+	gap      int           // Blank columns before the item
+	isWord   bool
+	spacing  int // CharSpacing of the run, added after a word that ends a run
+}
+
+// ComposeRuns lays out runs of different fonts and scales side by side. Each
+// run rests on the baseline of its font, so a 0.5x superscript or an icon font
+// sits on the same line as 2x text. Where two runs meet without a space, the
+// kerning between them is computed from the glyphs of both fonts, as it is
+// between letters of one font.
+//
+// The glyph settings of a run (scale, colors, gradient, pattern, fill
+// characters, bold, oblique, shadow, glow, extrusion, palette) come from its
+// Options, and the cells its effects paint are written with them. Each line
+// is styled on one canvas, so horizontal gradients and palettes run across
+// the whole line and glyphs are drawn over the effects of neighboring runs.
+// Alignment, line spacing and the background, padding and frame come from
+// options and apply to the whole composition. Layout transforms and
+// reflections are not applied.
+func ComposeRuns(runs []TextRun, fontData FontData, options RenderOptions) []string {
+	base := composeBaseOptions(options)
+
+	// Palette slots number the characters of the whole composition, so a
+	// palette continues from one run to the next
+	var fullText strings.Builder
+	for _, run := range runs {
+		fullText.WriteString(run.Text)
+	}
+	textLines := strings.Split(fullText.String(), "\n")
+	slotsByMode := make(map[PaletteMode][][]int)
+
+	lines := [][]composeItem{nil}
+	lineColumn := 0 // Rune index of the next chunk within its text line
+	previousWasWord := false
+	for _, run := range runs {
+		runOptions := base
+		if run.Options != nil {
+			runOptions = composeBaseOptions(*run.Options)
+		}
+		runFont := fontData
+		if run.Font != nil {
+			runFont = run.Font.FontData
+		}
+
+		// Split the run into lines, then into words and runs of spaces
+		runes := []rune(run.Text)
+		start := 0
+		for i := 0; i <= len(runes); i++ {
+			atBoundary := i == len(runes) || runes[i] == '\n' ||
+				(i > start && unicode.IsSpace(runes[i]) != unicode.IsSpace(runes[start]))
+			if !atBoundary {
+				continue
+			}
+			if i > start {
+				kerning := make(map[int]int)
+				for index, adjustment := range run.Kerning {
+					if index >= start && index < i {
+						kerning[index-start] = adjustment
+					}
+				}
+				var lineSlots []int
+				if runOptions.PaletteMode != NoPalette {
+					if _, ok := slotsByMode[runOptions.PaletteMode]; !ok {
+						slotsByMode[runOptions.PaletteMode] = paletteSlots(textLines, runOptions.PaletteMode)
+					}
+					lineSlots = slotsByMode[runOptions.PaletteMode][len(lines)-1][lineColumn : lineColumn+i-start]
+				}
+				item := renderComposeItem(string(runes[start:i]), runFont, runOptions, kerning, lineSlots)
+				lineColumn += i - start
+				if item.isWord && previousWasWord {
+					// The previous word ended another run; kern across the two fonts
+					line := lines[len(lines)-1]
+					previous := line[len(line)-1]
+					item.gap += kerningBetweenRuns(previous, item) + previous.spacing
+				}
+				previousWasWord = item.isWord
+				lines[len(lines)-1] = append(lines[len(lines)-1], item)
+			}
+			if i < len(runes) && runes[i] == '\n' {
+				lines = append(lines, nil)
+				lineColumn = 0
+				previousWasWord = false
+				start = i + 1
+			} else {
+				start = i
+			}
+		}
+	}
+
+	// Compose each line on a shared baseline, then align and stack the lines
+	var blocks [][]string
+	maxWidth := 0
+	for _, items := range lines {
+		block := composeLine(items, base)
+		blocks = append(blocks, block)
+		maxWidth = max(maxWidth, maxStyledWidth(block))
+	}
+
+	var result []string
+	for i, block := range blocks {
+		if len(block) == 0 {
+			if i > 0 {
+				result = append(result, "")
+			}
+			continue
+		}
+		if i > 0 && len(result) > 0 {
+			for range options.LineSpacing {
+				result = append(result, "")
+			}
+		}
+		result = append(result, applyAlignmentToTextLine(block, maxWidth, options.Alignment)...)
+	}
+	if len(result) == 0 {
+		return []string{}
+	}
+	return decorateBlock(padToCommonWidth(result), options)
+}
+
+// composeBaseOptions clears the options that apply to a whole composition
+// rather than to the glyphs of a single run
+func composeBaseOptions(options RenderOptions) RenderOptions {
+	options.VerticalLayout = false
+	options.Rotation = NoRotation
+	options.FlipHorizontal, options.FlipVertical = false, false
+	options.ReflectionEnabled = false
+	options.BackgroundColor, options.BackgroundGradientColor = "", ""
+	options.Padding = Padding{}
+	options.Frame = NoFrame
+	options.CustomKerning = nil
+	options.TextLines = nil
+	options.LineStyles = nil
+	return options
+}
+
+// renderComposeItem renders the glyphs of a word or a run of spaces of a text
+// run. lineSlots holds the palette slot of every rune, or nil without a palette.
+func renderComposeItem(run string, fontData FontData, options RenderOptions, kerning map[int]int, lineSlots []int) composeItem {
+	if strings.TrimSpace(run) == "" {
+		// Spaces advance like word boundaries in regular text
+		spaces := len([]rune(run))
+		return composeItem{gap: max(0, options.CharSpacing+int(math.Ceil(float64(spaces)*(0.5+float64(options.WordSpacing))))+kerning[0])}
+	}
+
+	if (options.ShadowEnabled || options.ExtrusionEnabled) && DetectHalfPixelUsageWithOptions(run, fontData, options) {
+		options.ShadowEnabled = false
+		options.ExtrusionEnabled = false
+	}
+
+	plain, sources := renderTextWithSources(run, fontData, options, kerning)
+	return composeItem{
+		plain:    plain,
+		sources:  sources.toSlots(lineSlots),
+		options:  options,
+		baseline: runBaselineRow(fontData, options, plain),
+		gap:      kerning[0],
+		isWord:   true,
+		spacing:  options.CharSpacing,
+	}
+}
+
+// kerningBetweenRuns returns the offset that makes the glyphs of two words
+// touch, with both words resting on a common baseline. It works like the
+// kerning between letters of one font: the right word starts that many
+// columns after the end of the left word.
+func kerningBetweenRuns(left, right composeItem) int {
+	above := max(left.baseline, right.baseline)
+	shift := func(rows []string, baseline int) []string {
+		return append(make([]string, above-baseline), rows...)
+	}
+	return computeKerning(shift(left.plain, left.baseline), shift(right.plain, right.baseline))
+}
+
+// baselineSampleChars are lowercase letters without descenders, used by
+// findCommonBaseline to locate the baseline of a font
+var baselineSampleChars = []string{"a", "e", "o", "x", "n", "m", "s", "c"}
+
+// runBaselineRow returns the row of a rendered run on which letters without
+// descenders rest. Glyphs are drawn from the top of the line, so the row is the
+// font's common baseline. Fonts without lowercase letters fall back to the
+// bottom of a reference letter, and then of the run itself.
+func runBaselineRow(fontData FontData, options RenderOptions, fallback []string) int {
+	for _, sample := range baselineSampleChars {
+		if _, ok := fontData.Characters[sample]; ok {
+			return findCommonBaseline(fontData, options.ScaleFactor)
+		}
+	}
+	for _, reference := range []string{"H", "I", "0"} {
+		if _, ok := fontData.Characters[reference]; !ok {
+			continue
+		}
+		rows := renderTextWithFont(reference, fontData, options, nil)
+		if _, end := emptyLineBounds(rows); end > 0 {
+			return end - 1
+		}
+	}
+	_, end := emptyLineBounds(fallback)
+	return max(0, end-1)
+}
+
+// composeLine places the glyphs of one line's items side by side with their
+// baselines on the same row, then styles the line on one canvas. Each word
+// paints its effects with its own options in turn; canvas layers keep every
+// glyph in front of the shadows, glows and extrusions of all words.
+func composeLine(items []composeItem, options RenderOptions) []string {
+	type placement struct {
+		item    composeItem
+		x, y    int // Position of the plain block's top-left cell
+		effects []Effect
+	}
+
+	above := 0
+	for _, item := range items {
+		if item.isWord {
+			above = max(above, item.baseline)
+		}
+	}
+
+	var placements []placement
+	penX, leftmost := 0, 0
+	for _, item := range items {
+		penX += item.gap
+		if !item.isWord {
+			continue
+		}
+		effects := styleEffects(item.options)
+		if !item.options.UseGradient || item.options.GradientColor == item.options.TextColor {
+			// Resolve the text color now, as words of other colors share the canvas
+			effects = append(effects, textColorEffect{color: item.options.TextColor})
+		}
+		placements = append(placements, placement{item: item, x: penX, y: above - item.baseline, effects: effects})
+		leftmost = min(leftmost, penX)
+		penX += maxRowLen(item.plain)
+	}
+	if len(placements) == 0 {
+		return nil
+	}
+
+	// Compose the glyphs of the line into one plain block, remembering the
+	// word and the palette slot behind every cell
+	blockWidth, blockHeight := 0, 0
+	for i := range placements {
+		placements[i].x -= leftmost
+		p := placements[i]
+		blockWidth = max(blockWidth, p.x+maxRowLen(p.item.plain))
+		blockHeight = max(blockHeight, p.y+len(p.item.plain))
+	}
+	block := make([][]rune, blockHeight)
+	owners := make(sourceMap, blockHeight)
+	sources := make(sourceMap, blockHeight)
+	for y := range block {
+		block[y] = []rune(strings.Repeat(" ", blockWidth))
+		owners[y] = make([]int, blockWidth)
+		sources[y] = make([]int, blockWidth)
+	}
+	for i, p := range placements {
+		for row, line := range p.item.plain {
+			for column, r := range []rune(line) {
+				if r == ' ' {
+					continue
+				}
+				x, y := p.x+column, p.y+row
+				block[y][x] = r
+				owners[y][x] = i + 1
+				sources[y][x] = p.item.sources.at(column, row)
+			}
+		}
+	}
+
+	text := make([]string, blockHeight)
+	for y, row := range block {
+		text[y] = strings.TrimRight(string(row), " ")
+	}
+
+	// Style the rows that hold glyphs, as regular lines are styled
+	start, end := emptyLineBounds(text)
+	if end <= start {
+		return nil
+	}
+	text, block, owners, sources = text[start:end], block[start:end], owners[start:end], sources[start:end]
+	blockHeight = end - start
+	for i := range placements {
+		placements[i].y -= start
+	}
+
+	// The canvas holds the effects of every word around the line
+	minX, maxX, minY, maxY := 0, blockWidth, 0, blockHeight
+	for _, p := range placements {
+		left, right, top, bottom := effectBounds(maxRowLen(p.item.plain), len(p.item.plain), p.effects)
+		minX, maxX = min(minX, p.x+left), max(maxX, p.x+right)
+		minY, maxY = min(minY, p.y+top), max(maxY, p.y+bottom)
+	}
+	canvas := &Canvas{
+		Cells:   make([][]Cell, maxY-minY),
+		Text:    text,
+		TextX:   -minX,
+		TextY:   -minY,
+		options: options,
+	}
+	for y := range canvas.Cells {
+		canvas.Cells[y] = make([]Cell, maxX-minX)
+		for x := range canvas.Cells[y] {
+			canvas.Cells[y][x] = Cell{Char: ' '}
+		}
+	}
+
+	// Paint each word's effects through a view of the canvas that only holds
+	// that word's glyphs, remembering the word that painted every cell
+	painters := make(sourceMap, len(canvas.Cells))
+	for y := range painters {
+		painters[y] = make([]int, len(canvas.Cells[y]))
+	}
+	for i, p := range placements {
+		before := make([][]Cell, len(canvas.Cells))
+		for y, row := range canvas.Cells {
+			before[y] = slices.Clone(row)
+		}
+		view := *canvas
+		view.Text = make([]string, blockHeight)
+		for y, row := range block {
+			masked := make([]rune, blockWidth)
+			for x, r := range row {
+				masked[x] = ' '
+				if owners[y][x] == i+1 {
+					masked[x] = r
+				}
+			}
+			view.Text[y] = strings.TrimRight(string(masked), " ")
+		}
+		view.options = p.item.options
+		view.sources = nil
+		if p.item.sources != nil {
+			view.sources = sources
+		}
+		for _, effect := range p.effects {
+			effect.Apply(&view)
+		}
+		for y, row := range canvas.Cells {
+			for x, cell := range row {
+				if cell != before[y][x] {
+					painters[y][x] = i + 1
+				}
+			}
+		}
+	}
+
+	// Write every cell with the pattern and fill characters of its word
+	var result []string
+	for y, row := range canvas.Cells {
+		var builder strings.Builder
+		for x, cell := range row {
+			cellOptions := options
+			if painter := painters.at(x, y); painter > 0 {
+				cellOptions = placements[painter-1].item.options
+			}
+			builder.WriteString(writeCell(cell, cellOptions, blockWidth, blockHeight))
+		}
+		result = append(result, strings.TrimRight(builder.String(), " "))
+	}
+	return stripEmptyLines(result)
+}
+
+// textColorEffect colors every painted cell without a color of its own
+type textColorEffect struct {
+	color string // Hex color
+}
+
+func (textColorEffect) Bounds(width, height int) Rect {
+	return Rect{Width: width, Height: height}
+}
+
+func (e textColorEffect) Apply(canvas *Canvas) {
+	for _, row := range canvas.Cells {
+		for x, cell := range row {
+			if cell.Char != ' ' && cell.Color == "" {
+				row[x].Color = e.color
+			}
+		}
+	}
+}
+
+// maxStyledWidth returns the visible width of the widest styled row
+func maxStyledWidth(rows []string) int {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len([]rune(stripANSI(row))))
+	}
+	return width
+}
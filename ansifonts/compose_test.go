@@ -0,0 +1,102 @@
+package ansifonts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComposeRuns_MatchesSingleRun(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	tests := []struct {
+		name    string
+		options func(*RenderOptions)
+	}{
+		{"horizontal gradient", func(o *RenderOptions) {
+			o.TextColor, o.GradientColor, o.UseGradient, o.GradientDirection = "#FF0000", "#0000FF", true, LeftRight
+		}},
+		{"vertical gradient", func(o *RenderOptions) {
+			o.TextColor, o.GradientColor, o.UseGradient, o.GradientDirection = "#FF0000", "#0000FF", true, UpDown
+		}},
+		{"palette", func(o *RenderOptions) { o.PaletteMode = PalettePerChar }},
+		{"shadow", func(o *RenderOptions) {
+			o.ShadowEnabled, o.ShadowHorizontalOffset, o.ShadowVerticalOffset = true, 2, 1
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultRenderOptions()
+			tt.options(&options)
+			// Splitting one style into runs must not restart the gradient or palette
+			expected := RenderTextWithOptions("ABCD", font, options)
+			got := ComposeRuns([]TextRun{{Text: "A"}, {Text: "B"}, {Text: "CD"}}, font.FontData, options)
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("expected:\n%s\ngot:\n%s", joinLines(expected), joinLines(got))
+			}
+		})
+	}
+}
+
+func TestComposeRuns_GlyphsCoverNeighboringEffects(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	options := DefaultRenderOptions()
+	red := options
+	red.TextColor = "#FF0000"
+	runs := []TextRun{{Text: "A", Options: &red}, {Text: "B"}}
+
+	plain := ComposeRuns(runs, font.FontData, options)
+	options.GlowEnabled = true
+	red.GlowEnabled = true
+	glowing := ComposeRuns(runs, font.FontData, options)
+
+	// Every glyph cell keeps its block where the glow of the other word reaches
+	marginX, marginY := glowCellMargins(glowRadiusPixels(options))
+	for y, line := range plain {
+		for x, r := range []rune(stripANSI(line)) {
+			if r == ' ' {
+				continue
+			}
+			glowRow := []rune(stripANSI(glowing[y+marginY]))
+			if x+marginX >= len(glowRow) || glowRow[x+marginX] != r {
+				t.Errorf("glyph cell (%d, %d) was painted over:\n%s", x, y, joinLines(glowing))
+				return
+			}
+		}
+	}
+}
+
+func TestComposeRuns_PerRunPatternAndFill(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	striped := DefaultRenderOptions()
+	striped.Pattern, striped.PatternColor = StripePattern, "#0000FF"
+	filled := DefaultRenderOptions()
+	filled.Fill.FullBlock = '#'
+	filled.ShadowEnabled, filled.ShadowHorizontalOffset = true, 1
+	filled.Fill.LightShade = ':'
+
+	// Each run keeps its own pattern and fill characters, including those of
+	// its shadow, and neither reaches the other run
+	lines := ComposeRuns([]TextRun{
+		{Text: "A", Options: &striped},
+		{Text: "B", Options: &filled},
+	}, font.FontData, DefaultRenderOptions())
+	count := func(lines []string, part string) int {
+		return strings.Count(strings.Join(lines, "\n"), part)
+	}
+	alone := func(text string, options RenderOptions) []string {
+		return RenderTextWithOptions(text, font, options)
+	}
+
+	const stripe = "\x1b[48;2;0;0;255m"
+	if got, expected := count(lines, stripe), count(alone("A", striped), stripe); got != expected || got == 0 {
+		t.Errorf("expected %d striped cells, got %d", expected, got)
+	}
+	for _, char := range []string{"#", ":"} {
+		if got, expected := count(lines, char), count(alone("B", filled), char); got != expected || got == 0 {
+			t.Errorf("expected %d %q cells, got %d", expected, char, got)
+		}
+	}
+	if count(lines, "█") != 0 {
+		t.Errorf("expected no full blocks, got:\n%s", joinLines(lines))
+	}
+}
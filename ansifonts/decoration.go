@@ -0,0 +1,181 @@
+package ansifonts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// frameChars holds the box-drawing characters of a frame style
+type frameChars struct {
+	topLeft, topRight, bottomLeft, bottomRight rune
+	horizontal, vertical                       rune
+}
+
+// frameStyles maps each frame style to its box-drawing characters
+var frameStyles = map[FrameStyle]frameChars{
+	SingleFrame:  {'┌', '┐', '└', '┘', '─', '│'},
+	DoubleFrame:  {'╔', '╗', '╚', '╝', '═', '║'},
+	RoundedFrame: {'╭', '╮', '╰', '╯', '─', '│'},
+	HeavyFrame:   {'┏', '┓', '┗', '┛', '━', '┃'},
+	ASCIIFrame:   {'+', '+', '+', '+', '-', '|'},
+}
+
+// hasDecoration reports whether options add a background, padding or frame
+func hasDecoration(options RenderOptions) bool {
+	return options.BackgroundColor != "" || options.Frame != NoFrame || options.Padding != (Padding{})
+}
+
+// decorationOffset returns the cells that padding and the frame add to the
+// left and top of the rendered block
+func decorationOffset(options RenderOptions) (int, int) {
+	if !hasDecoration(options) {
+		return 0, 0
+	}
+	x, y := options.Padding.Left, options.Padding.Top
+	if _, ok := frameStyles[options.Frame]; ok {
+		x++
+		y++
+	}
+	return x, y
+}
+
+// decorateBlock surrounds the rendered lines with padding and an optional frame,
+// and paints the background behind every cell of the resulting box. It runs
+// after the final width padding, so all lines already share the same width.
+func decorateBlock(lines []string, options RenderOptions) []string {
+	if len(lines) == 0 || !hasDecoration(options) {
+		return lines
+	}
+
+	// Split lines into styled cells so each one can carry a background
+	var rows [][]string
+	contentWidth := 0
+	for _, line := range lines {
+		cells := splitStyledCells(line)
+		contentWidth = max(contentWidth, len(cells))
+		rows = append(rows, cells)
+	}
+
+	pad := options.Padding
+	innerWidth := pad.Left + contentWidth + pad.Right
+	var box [][]string
+	blankRow := func() []string {
+		row := make([]string, innerWidth)
+		for i := range row {
+			row[i] = " "
+		}
+		return row
+	}
+	for range pad.Top {
+		box = append(box, blankRow())
+	}
+	for _, cells := range rows {
+		row := blankRow()
+		copy(row[pad.Left:], cells)
+		box = append(box, row)
+	}
+	for range pad.Bottom {
+		box = append(box, blankRow())
+	}
+
+	// Wrap the padded box in the frame
+	if chars, ok := frameStyles[options.Frame]; ok {
+		frameColor := options.FrameColor
+		if frameColor == "" {
+			frameColor = options.TextColor
+		}
+		r, g, b := hexToRGB(frameColor)
+		frameCell := func(ch rune) string {
+			return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", r, g, b, string(ch))
+		}
+
+		top := []string{frameCell(chars.topLeft)}
+		bottom := []string{frameCell(chars.bottomLeft)}
+		for range innerWidth {
+			top = append(top, frameCell(chars.horizontal))
+			bottom = append(bottom, frameCell(chars.horizontal))
+		}
+		top = append(top, frameCell(chars.topRight))
+		bottom = append(bottom, frameCell(chars.bottomRight))
+
+		framed := [][]string{top}
+		for _, row := range box {
+			framedRow := append([]string{frameCell(chars.vertical)}, row...)
+			framed = append(framed, append(framedRow, frameCell(chars.vertical)))
+		}
+		box = append(framed, bottom)
+	}
+
+	// Serialize, painting the background behind every cell
+	result := make([]string, len(box))
+	for y, row := range box {
+		var builder strings.Builder
+		for x, cell := range row {
+			if options.BackgroundColor == "" {
+				builder.WriteString(cell)
+				continue
+			}
+			r, g, b := hexToRGB(backgroundColorAt(options, x, y, len(row), len(box)))
+			builder.WriteString(fmt.Sprintf("\x1b[48;2;%d;%d;%dm", r, g, b))
+			builder.WriteString(cell)
+			if cell == " " {
+				builder.WriteString("\x1b[0m")
+			}
+		}
+		result[y] = builder.String()
+	}
+	return result
+}
+
+// backgroundColorAt returns the background color of cell (x, y) in a box of the
+// given size, following the background gradient when one is set
+func backgroundColorAt(options RenderOptions, x, y, width, height int) string {
+	if options.BackgroundGradientColor == "" || options.BackgroundGradientColor == options.BackgroundColor {
+		return options.BackgroundColor
+	}
+
+	var factor float64
+	switch options.BackgroundGradientDirection {
+	case UpDown, DownUp:
+		if height > 1 {
+			factor = float64(y) / float64(height-1)
+		}
+	case LeftRight, RightLeft:
+		if width > 1 {
+			factor = float64(x) / float64(width-1)
+		}
+	}
+	if options.BackgroundGradientDirection == DownUp || options.BackgroundGradientDirection == RightLeft {
+		factor = 1.0 - factor
+	}
+	return blendHex(options.BackgroundColor, options.BackgroundGradientColor, factor)
+}
+
+// splitStyledCells splits a rendered line into cells. Each cell holds one
+// character with the escape sequences that style it and its trailing reset.
+func splitStyledCells(line string) []string {
+	var cells []string
+	var prefix strings.Builder
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\x1b' {
+			// Collect the escape sequence up to its terminating letter
+			j := i + 1
+			for j < len(runes) && !((runes[j] >= 'A' && runes[j] <= 'Z') || (runes[j] >= 'a' && runes[j] <= 'z')) {
+				j++
+			}
+			prefix.WriteString(string(runes[i:min(j+1, len(runes))]))
+			i = j
+			continue
+		}
+
+		cell := prefix.String() + string(runes[i])
+		prefix.Reset()
+		if strings.HasPrefix(string(runes[i+1:min(i+5, len(runes))]), "\x1b[0m") {
+			cell += "\x1b[0m"
+			i += 4
+		}
+		cells = append(cells, cell)
+	}
+	return cells
+}
@@ -0,0 +1,93 @@
+package ansifonts
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// plainRows strips the escape sequences from styled rows
+func plainRows(lines []string) []string {
+	rows := make([]string, len(lines))
+	for i, line := range lines {
+		rows[i] = stripANSI(line)
+	}
+	return rows
+}
+
+func TestDecorateBlock_Padding(t *testing.T) {
+	options := DefaultRenderOptions()
+	options.Padding = Padding{Top: 1, Right: 2, Bottom: 1, Left: 1}
+	expected := []string{
+		"     ",
+		" ab  ",
+		" cd  ",
+		"     ",
+	}
+	if got := plainRows(decorateBlock([]string{"ab", "cd"}, options)); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, got)
+	}
+}
+
+func TestDecorateBlock_Frames(t *testing.T) {
+	// Every frame adds one cell on each side, outside the padding
+	tests := []struct {
+		frame    FrameStyle
+		expected []string
+	}{
+		{SingleFrame, []string{"┌────┐", "│ ab │", "│ cd │", "└────┘"}},
+		{DoubleFrame, []string{"╔════╗", "║ ab ║", "║ cd ║", "╚════╝"}},
+		{RoundedFrame, []string{"╭────╮", "│ ab │", "│ cd │", "╰────╯"}},
+		{HeavyFrame, []string{"┏━━━━┓", "┃ ab ┃", "┃ cd ┃", "┗━━━━┛"}},
+		{ASCIIFrame, []string{"+----+", "| ab |", "| cd |", "+----+"}},
+	}
+	for _, tt := range tests {
+		options := DefaultRenderOptions()
+		options.Frame = tt.frame
+		options.Padding = Padding{Left: 1, Right: 1}
+		if got := plainRows(decorateBlock([]string{"ab", "cd"}, options)); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("frame %d: expected:\n%s\ngot:\n%s", tt.frame, joinLines(tt.expected), joinLines(got))
+		}
+		if x, y := decorationOffset(options); x != 2 || y != 1 {
+			t.Errorf("frame %d: expected the text offset by (2, 1), got (%d, %d)", tt.frame, x, y)
+		}
+	}
+}
+
+func TestDecorateBlock_Background(t *testing.T) {
+	background := regexp.MustCompile(`\x1b\[48;2;(\d+;\d+;\d+)m`)
+	options := DefaultRenderOptions()
+	options.Frame = SingleFrame
+	options.BackgroundColor = "#102030"
+	options.BackgroundGradientColor = "#FF2030"
+	options.BackgroundGradientDirection = LeftRight
+
+	// Every cell of the box, the frame and the styled glyphs included, gets
+	// a background that follows the gradient across the box
+	styled := "\x1b[38;2;255;0;0mA\x1b[0m "
+	for y, line := range decorateBlock([]string{styled}, options) {
+		colors := background.FindAllStringSubmatch(line, -1)
+		if len(colors) != 4 {
+			t.Fatalf("row %d: expected a background behind 4 cells, got %d in %q", y, len(colors), line)
+		}
+		if colors[0][1] != "16;32;48" || colors[3][1] != "255;32;48" {
+			t.Errorf("row %d: expected the gradient from 16;32;48 to 255;32;48, got %s to %s", y, colors[0][1], colors[3][1])
+		}
+	}
+	if got := plainRows(decorateBlock([]string{styled}, options)); !reflect.DeepEqual(got, []string{"┌──┐", "│A │", "└──┘"}) {
+		t.Errorf("unexpected box:\n%s", joinLines(got))
+	}
+}
+
+func TestSplitStyledCells(t *testing.T) {
+	line := "\x1b[38;2;255;0;0mA\x1b[0m \x1b[1m\x1b[38;2;0;0;255mB\x1b[0mC"
+	expected := []string{"\x1b[38;2;255;0;0mA\x1b[0m", " ", "\x1b[1m\x1b[38;2;0;0;255mB\x1b[0m", "C"}
+	got := splitStyledCells(line)
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if joined := strings.Join(got, ""); joined != line {
+		t.Errorf("expected the cells to join back into the line, got %q", joined)
+	}
+}
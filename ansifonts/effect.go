@@ -0,0 +1,365 @@
+package ansifonts
+
+import "math"
+
+// Layer identifies the part of the styled text a canvas cell belongs to.
+// Layers are listed from back to front.
+type Layer int
+
+const (
+	GlowLayer       Layer = iota // Shades around the glyphs
+	ReflectionLayer              // Mirrored glyphs below the text
+	ShadowLayer                  // Offset copy of the glyphs
+	ExtrusionLayer               // Solid layers between the glyphs and their shadow
+	TextLayer                    // The glyphs themselves
+)
+
+// Cell is a character cell of a Canvas
+type Cell struct {
+	Char   rune    // Character, ' ' for an empty cell
+	Color  string  // Hex color (empty takes the text color)
+	Layer  Layer   // Layer the cell was painted on
+	Row    int     // Row of the text block the cell was drawn from
+	Column int     // Column of the text block the cell was drawn from
+	Depth  float64 // Distance into a reflection or extrusion, from 0 (near) to 1 (far)
+	FadeTo string  // Hex color Color is blended toward by Depth when written (empty for none)
+}
+
+// Canvas is the grid of cells a text block is styled on. It is large enough
+// for the bounds of every effect, and the plain text block sits at TextX, TextY.
+type Canvas struct {
+	Cells [][]Cell // Rows of cells
+	Text  []string // Plain rows of the text block
+	TextX int      // Column of the text block's top-left cell
+	TextY int      // Row of the text block's top-left cell
+
+	sources sourceMap     // Palette slot behind every cell of Text, nil without a palette
+	lines   *lineColors   // Colors of the text lines sources refer to, nil for palette slots
+	options RenderOptions // Options the palette colors come from
+}
+
+// Effect styles a text block on a Canvas. Bounds returns the area the effect
+// paints for a text block of the given size, relative to the block's top-left
+// cell, so the canvas can be made large enough. Apply paints the canvas.
+//
+// The built-in effects are derived from RenderOptions: glow, reflection,
+// ShadowEffect, extrusion, the glyphs, then GradientEffect. Effects listed in
+// RenderOptions.Effects run after them, in order.
+type Effect interface {
+	Bounds(width, height int) Rect
+	Apply(canvas *Canvas)
+}
+
+// Width returns the number of columns of the canvas
+func (c *Canvas) Width() int {
+	if len(c.Cells) == 0 {
+		return 0
+	}
+	return len(c.Cells[0])
+}
+
+// Height returns the number of rows of the canvas
+func (c *Canvas) Height() int {
+	return len(c.Cells)
+}
+
+// At returns the cell at (x, y), or an empty cell outside the canvas
+func (c *Canvas) At(x, y int) Cell {
+	if y < 0 || y >= len(c.Cells) || x < 0 || x >= len(c.Cells[y]) {
+		return Cell{Char: ' '}
+	}
+	return c.Cells[y][x]
+}
+
+// Paint sets the cell at (x, y) unless it lies outside the canvas or the cell
+// there is on a layer in front of the new one
+func (c *Canvas) Paint(x, y int, cell Cell) {
+	if y < 0 || y >= len(c.Cells) || x < 0 || x >= len(c.Cells[y]) {
+		return
+	}
+	if current := c.Cells[y][x]; current.Char != ' ' && current.Layer > cell.Layer {
+		return
+	}
+	c.Cells[y][x] = cell
+}
+
+// TextCell returns cell (x, y) of the text block as the glyphs paint it: its
+// character (' ' for blank cells), its position and, when a palette or a line
+// style colors the text, its color
+func (c *Canvas) TextCell(x, y int) Cell {
+	cell := Cell{Char: ' ', Layer: TextLayer, Row: y, Column: x}
+	if y >= 0 && y < len(c.Text) {
+		if runes := []rune(c.Text[y]); x >= 0 && x < len(runes) {
+			cell.Char = runes[x]
+		}
+	}
+	if source := c.sources.at(x, y); source > 0 {
+		if c.lines != nil {
+			cell.Color = c.lines.color(source-1, x, y)
+		} else {
+			cell.Color = paletteColor(c.options, source-1)
+		}
+	}
+	return cell
+}
+
+// ShadowEffect paints a copy of the glyphs offset by X and Y cells
+type ShadowEffect struct {
+	X     int    // Horizontal offset in cells
+	Y     int    // Vertical offset in cells
+	Char  rune   // Character of the shadow (0 keeps the glyph characters)
+	Color string // Hex color of the shadow (empty takes the text color or gradient)
+}
+
+// Bounds returns the text block moved by the shadow offsets
+func (e ShadowEffect) Bounds(width, height int) Rect {
+	return Rect{X: e.X, Y: e.Y, Width: width, Height: height}
+}
+
+// Apply paints the shadow behind the glyphs
+func (e ShadowEffect) Apply(canvas *Canvas) {
+	for y, line := range canvas.Text {
+		for x, r := range []rune(line) {
+			if r == ' ' {
+				continue
+			}
+			cell := canvas.TextCell(x, y)
+			cell.Layer = ShadowLayer
+			if e.Char != 0 {
+				cell.Char = e.Char
+			}
+			if cell.Color == "" || canvas.lines != nil {
+				// Line colors stay on the glyphs, as they do for separately styled lines
+				cell.Color = e.Color
+			}
+			canvas.Paint(canvas.TextX+x+e.X, canvas.TextY+y+e.Y, cell)
+		}
+	}
+}
+
+// GradientEffect colors every painted cell without a color of its own with a
+// gradient from one color to another. Vertical gradients follow the text block
+// row each cell was drawn from; horizontal ones run across the whole canvas.
+type GradientEffect struct {
+	From      string // Hex start color
+	To        string // Hex end color
+	Direction GradientDirection
+}
+
+// Bounds returns the text block, as the gradient only recolors painted cells
+func (e GradientEffect) Bounds(width, height int) Rect {
+	return Rect{Width: width, Height: height}
+}
+
+// Apply colors the cells
+func (e GradientEffect) Apply(canvas *Canvas) {
+	startR, startG, startB := hexToRGB(e.From)
+	endR, endG, endB := hexToRGB(e.To)
+	blockHeight, canvasWidth := len(canvas.Text), canvas.Width()
+
+	for _, row := range canvas.Cells {
+		for x, cell := range row {
+			if cell.Char == ' ' || cell.Color != "" {
+				continue
+			}
+			var factor float64
+			switch e.Direction {
+			case UpDown:
+				if blockHeight > 1 {
+					factor = float64(cell.Row) / float64(blockHeight-1)
+				}
+			case DownUp:
+				if blockHeight > 1 {
+					factor = 1.0 - (float64(cell.Row) / float64(blockHeight-1))
+				}
+			case LeftRight, RightLeft:
+				// The whole canvas width keeps the gradient consistent across
+				// glyphs of different heights
+				if canvasWidth > 1 {
+					factor = float64(x) / float64(canvasWidth-1)
+				}
+				if e.Direction == RightLeft {
+					factor = 1.0 - factor
+				}
+			}
+			r := int(float64(startR) + factor*float64(endR-startR))
+			g := int(float64(startG) + factor*float64(endG-startG))
+			b := int(float64(startB) + factor*float64(endB-startB))
+			row[x].Color = rgbToHex(clamp(r, 0, 255), clamp(g, 0, 255), clamp(b, 0, 255))
+		}
+	}
+}
+
+// textEffect paints the glyphs
+type textEffect struct{}
+
+func (textEffect) Bounds(width, height int) Rect {
+	return Rect{Width: width, Height: height}
+}
+
+func (textEffect) Apply(canvas *Canvas) {
+	for y, line := range canvas.Text {
+		for x, r := range []rune(line) {
+			if r != ' ' {
+				canvas.Paint(canvas.TextX+x, canvas.TextY+y, canvas.TextCell(x, y))
+			}
+		}
+	}
+}
+
+// reflectionEffect paints the mirrored glyphs below the text
+type reflectionEffect struct {
+	gap           int
+	heightPercent int
+	fade          ReflectionFade
+	color         string
+}
+
+func (e reflectionEffect) Bounds(width, height int) Rect {
+	return Rect{Y: height + e.gap, Width: width, Height: reflectionRowCount(height, e.heightPercent)}
+}
+
+func (e reflectionEffect) Apply(canvas *Canvas) {
+	rows := reflectBlock(canvas.Text, e.heightPercent)
+	blockHeight := len(canvas.Text)
+	top := canvas.TextY + blockHeight + e.gap
+	for y, line := range rows {
+		depth := float64(y+1) / float64(len(rows)+1)
+		for x, r := range []rune(line) {
+			if r == ' ' {
+				continue
+			}
+			// The mirrored source row keeps gradients and palettes mirrored too
+			cell := canvas.TextCell(x, blockHeight-1-y)
+			cell.Char = reflectionChar(r, y, len(rows), e.fade)
+			cell.Layer = ReflectionLayer
+			cell.Depth = depth
+			if e.fade != FadeShade {
+				cell.FadeTo = e.color
+			}
+			canvas.Paint(canvas.TextX+x, top+y, cell)
+		}
+	}
+}
+
+// extrusionEffect paints solid layers from the glyphs along the shadow offsets
+type extrusionEffect struct {
+	x, y        int
+	nearColor   string
+	farColor    string
+	long        bool // Extend the layers to the canvas edge
+	belowShadow bool // Stop one layer short, leaving the shadow visible
+}
+
+func (e extrusionEffect) Bounds(width, height int) Rect {
+	return Rect{X: e.x, Y: e.y, Width: width, Height: height}
+}
+
+func (e extrusionEffect) Apply(canvas *Canvas) {
+	if e.x == 0 && e.y == 0 {
+		return
+	}
+	steps := max(abs(e.x), abs(e.y))
+	stepX := float64(e.x) / float64(steps)
+	stepY := float64(e.y) / float64(steps)
+	layers := steps
+	if e.long {
+		layers = longShadowLayers(stepX, stepY, canvas.Width(), canvas.Height())
+	} else if e.belowShadow {
+		layers = steps - 1
+	}
+
+	// Draw the farthest layer first so nearer layers cover it
+	for k := layers; k >= 1; k-- {
+		depth := 0.0
+		if layers > 1 {
+			depth = float64(k-1) / float64(layers-1)
+		}
+		offsetX := canvas.TextX + int(math.Round(float64(k)*stepX))
+		offsetY := canvas.TextY + int(math.Round(float64(k)*stepY))
+		color := blendHex(e.nearColor, e.farColor, depth)
+		for y, line := range canvas.Text {
+			for x, r := range []rune(line) {
+				if r != ' ' {
+					canvas.Paint(offsetX+x, offsetY+y, Cell{Char: r, Color: color, Layer: ExtrusionLayer, Row: y, Column: x, Depth: depth})
+				}
+			}
+		}
+	}
+}
+
+// styleEffects returns the effects options ask for, with the built-in ones
+// first and in the order they are painted
+func styleEffects(options RenderOptions) []Effect {
+	var effects []Effect
+	isGradient := options.UseGradient && options.GradientColor != options.TextColor
+
+	if options.GlowEnabled {
+		effects = append(effects, glowEffect{radius: glowRadiusPixels(options), color: options.GlowColor})
+	}
+	if options.ReflectionEnabled {
+		reflectionColor := options.ReflectionColor
+		if reflectionColor == "" {
+			reflectionColor = "#000000"
+		}
+		effects = append(effects, reflectionEffect{
+			gap:           options.ReflectionGap,
+			heightPercent: options.ReflectionHeight,
+			fade:          options.ReflectionFade,
+			color:         reflectionColor,
+		})
+	}
+	if options.ShadowEnabled {
+		style := shadowStyleOptions[options.ShadowStyle]
+		shadow := ShadowEffect{X: options.ShadowHorizontalOffset, Y: options.ShadowVerticalOffset, Char: style.Char, Color: style.Hex}
+		if isGradient {
+			// The gradient runs through the shadow as well
+			shadow.Color = ""
+		}
+		effects = append(effects, shadow)
+	}
+	if options.ExtrusionEnabled {
+		// Darken the text color when no ramp is given
+		nearColor := options.ExtrusionNearColor
+		if nearColor == "" {
+			nearColor = blendHex(options.TextColor, "#000000", 0.4)
+		}
+		farColor := options.ExtrusionFarColor
+		if farColor == "" {
+			if options.ExtrusionNearColor != "" {
+				farColor = nearColor
+			} else {
+				farColor = blendHex(options.TextColor, "#000000", 0.75)
+			}
+		}
+		effects = append(effects, extrusionEffect{
+			x:           options.ShadowHorizontalOffset,
+			y:           options.ShadowVerticalOffset,
+			nearColor:   nearColor,
+			farColor:    farColor,
+			long:        options.LongShadow,
+			belowShadow: options.ShadowEnabled,
+		})
+	}
+	effects = append(effects, textEffect{})
+	if isGradient {
+		effects = append(effects, GradientEffect{From: options.TextColor, To: options.GradientColor, Direction: options.GradientDirection})
+	}
+	return append(effects, options.Effects...)
+}
+
+// effectBounds returns the extent of a canvas holding a text block of the
+// given size and everything the effects paint around it. The text block's
+// top-left cell lands at (-minX, -minY).
+func effectBounds(blockWidth, blockHeight int, effects []Effect) (minX, maxX, minY, maxY int) {
+	minX, maxX = 0, blockWidth
+	minY, maxY = 0, blockHeight
+	for _, effect := range effects {
+		bounds := effect.Bounds(blockWidth, blockHeight)
+		minX = min(minX, bounds.X)
+		maxX = max(maxX, bounds.X+bounds.Width)
+		minY = min(minY, bounds.Y)
+		maxY = max(maxY, bounds.Y+bounds.Height)
+	}
+	return minX, maxX, minY, maxY
+}
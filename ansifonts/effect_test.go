@@ -0,0 +1,148 @@
+package ansifonts
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of the effect tests")
+
+// loadTestFont loads a bundled font or fails the test
+func loadTestFont(t *testing.T, name string) *Font {
+	t.Helper()
+	font, err := LoadFont(name)
+	if err != nil {
+		t.Fatalf("failed to load font %s: %v", name, err)
+	}
+	return font
+}
+
+// joinLines joins rendered lines for failure messages
+func joinLines(lines []string) string {
+	return strings.Join(lines, "\n")
+}
+
+// effectCases render the built-in effects with the options users reach for
+var effectCases = []struct {
+	name    string
+	options func(*RenderOptions)
+}{
+	{"shadow", func(o *RenderOptions) {
+		o.ShadowEnabled, o.ShadowHorizontalOffset, o.ShadowVerticalOffset = true, 2, 1
+	}},
+	{"shadow_style", func(o *RenderOptions) {
+		o.ShadowEnabled, o.ShadowHorizontalOffset, o.ShadowVerticalOffset, o.ShadowStyle = true, -1, 1, MediumShade
+	}},
+	{"gradient_right", func(o *RenderOptions) {
+		o.TextColor, o.GradientColor, o.UseGradient, o.GradientDirection = "#FF0000", "#0000FF", true, LeftRight
+	}},
+	{"gradient_up", func(o *RenderOptions) {
+		o.TextColor, o.GradientColor, o.UseGradient, o.GradientDirection = "#00FF00", "#FFFF00", true, DownUp
+	}},
+	{"gradient_shadow", func(o *RenderOptions) {
+		o.TextColor, o.GradientColor, o.UseGradient, o.GradientDirection = "#FF0000", "#0000FF", true, UpDown
+		o.ShadowEnabled, o.ShadowHorizontalOffset, o.ShadowVerticalOffset = true, 1, 1
+	}},
+	{"reflection", func(o *RenderOptions) {
+		o.ReflectionEnabled, o.ReflectionGap, o.ReflectionHeight = true, 1, 60
+	}},
+	{"reflection_color", func(o *RenderOptions) {
+		o.TextColor = "#00FFFF"
+		o.ReflectionEnabled, o.ReflectionHeight, o.ReflectionFade, o.ReflectionColor = true, 100, FadeShadeColor, "#000080"
+	}},
+	{"extrusion", func(o *RenderOptions) {
+		o.TextColor = "#FFAA00"
+		o.ExtrusionEnabled, o.ShadowHorizontalOffset, o.ShadowVerticalOffset = true, 3, 2
+	}},
+	{"extrusion_ramp", func(o *RenderOptions) {
+		o.ExtrusionEnabled, o.ShadowHorizontalOffset, o.ShadowVerticalOffset = true, -2, 2
+		o.ExtrusionNearColor, o.ExtrusionFarColor = "#FF0000", "#200000"
+	}},
+}
+
+// TestEffects_MatchGolden checks the effects against output recorded before
+// they ran on a canvas. Run with -update to record new output.
+func TestEffects_MatchGolden(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	for _, tt := range effectCases {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultRenderOptions()
+			tt.options(&options)
+			got := joinLines(RenderTextWithOptions("Hi!\nok", font, options)) + "\n"
+
+			path := filepath.Join("testdata", "effects", tt.name+".golden")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			expected, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if got != string(expected) {
+				t.Errorf("output differs from %s:\nexpected:\n%s\ngot:\n%s", path, expected, got)
+			}
+		})
+	}
+}
+
+// ringEffect frames the text block with '#' and fills its blank cells with
+// '*', all on the glow layer
+type ringEffect struct{}
+
+func (ringEffect) Bounds(width, height int) Rect {
+	return Rect{X: -1, Y: -1, Width: width + 2, Height: height + 2}
+}
+
+func (ringEffect) Apply(canvas *Canvas) {
+	for y := 0; y < canvas.Height(); y++ {
+		for x := 0; x < canvas.Width(); x++ {
+			char := '*'
+			if y == 0 || x == 0 || y == canvas.Height()-1 || x == canvas.Width()-1 {
+				char = '#'
+			}
+			canvas.Paint(x, y, Cell{Char: char, Layer: GlowLayer})
+		}
+	}
+}
+
+func TestRenderOptions_CustomEffect(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	plain := RenderTextWithOptions("Hi", font, DefaultRenderOptions())
+	width := 0
+	for _, line := range plain {
+		width = max(width, len([]rune(stripANSI(line))))
+	}
+
+	// The canvas grows by the effect's bounds, and the glyphs stay in front
+	// of it although custom effects run last
+	expected := []string{strings.Repeat("#", width+2)}
+	for _, line := range plain {
+		row := []rune(stripANSI(line))
+		for len(row) < width {
+			row = append(row, ' ')
+		}
+		expected = append(expected, "#"+strings.ReplaceAll(string(row), " ", "*")+"#")
+	}
+	expected = append(expected, strings.Repeat("#", width+2))
+
+	options := DefaultRenderOptions()
+	options.Effects = []Effect{ringEffect{}}
+	lines := RenderTextWithOptions("Hi", font, options)
+	got := make([]string, len(lines))
+	for i, line := range lines {
+		got[i] = stripANSI(line)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%s\ngot:\n%s", joinLines(expected), joinLines(got))
+	}
+}
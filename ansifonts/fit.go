@@ -0,0 +1,198 @@
+package ansifonts
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrNoFit is returned by FitText when no candidate rendering fits the box
+var ErrNoFit = errors.New("text does not fit in the target box")
+
+// defaultFitScales are the scale factors FitText tries when FitOptions.Scales is empty
+var defaultFitScales = []float64{4.0, 2.0, 1.0, 0.5}
+
+// FitOptions describes the box FitText fills and what it may change to fill it
+type FitOptions struct {
+	Width        int       // Box width in cells, 0 for no limit
+	Height       int       // Box height in cells, 0 for no limit
+	Fonts        []*Font   // Candidate fonts; empty uses the font passed to FitText
+	Scales       []float64 // Candidate scale factors; empty tries 4, 2, 1 and 0.5
+	FixedSpacing bool      // Keep the character and word spacing instead of tightening it
+	AllowWrap    bool      // Allow breaking lines at spaces
+}
+
+// FitResult is the rendering FitText picked
+type FitResult struct {
+	Lines   []string      // Rendered output
+	Text    string        // Text as rendered, including the line breaks added by wrapping
+	Font    *Font         // Font of the rendering
+	Options RenderOptions // Options of the rendering, with the chosen scale and spacing
+	Width   int           // Width of the rendered block in cells
+	Height  int           // Height of the rendered block in cells
+}
+
+// FitText renders text as large as possible within a box. For every
+// candidate font it tries the scale factors from the largest down, first with
+// the spacing of options, then with tighter character and word spacing, and
+// then, when allowed, with lines wrapped at spaces. Among the fonts it picks
+// the rendering with the tallest glyphs, then the one covering the most cells.
+//
+// Wrapped text drops CustomKerning and LineStyles, whose line and rune indices
+// no longer match. ErrNoFit is returned when nothing fits.
+func FitText(text string, font *Font, options RenderOptions, fit FitOptions) (FitResult, error) {
+	fonts := fit.Fonts
+	if len(fonts) == 0 {
+		fonts = []*Font{font}
+	}
+	scales := slices.Clone(fit.Scales)
+	if len(scales) == 0 {
+		scales = slices.Clone(defaultFitScales)
+	}
+	slices.Sort(scales)
+	slices.Reverse(scales)
+
+	var best FitResult
+	bestGlyphHeight, bestArea := -1, -1
+	for _, candidate := range fonts {
+		if candidate == nil {
+			continue
+		}
+		for _, scale := range scales {
+			scaled := options
+			scaled.ScaleFactor = scale
+			result, ok := fitWithFont(text, candidate, scaled, fit)
+			if !ok {
+				continue
+			}
+
+			// Smaller scales of the same font only shrink the glyphs
+			glyphHeight := tallestLine(measureTextWithFont(result.Text, candidate.FontData, result.Options))
+			area := result.Width * result.Height
+			if glyphHeight > bestGlyphHeight || (glyphHeight == bestGlyphHeight && area > bestArea) {
+				best, bestGlyphHeight, bestArea = result, glyphHeight, area
+			}
+			break
+		}
+	}
+	if bestGlyphHeight < 0 {
+		return FitResult{}, ErrNoFit
+	}
+	return best, nil
+}
+
+// fitWithFont returns the first rendering of text with the font and scale of
+// options that fits the box, trying tighter spacing before wrapping. Each text
+// is first rendered with the tightest spacing: spacing never changes the
+// height, so a text that is too tall there is too tall with any spacing, and
+// wrapping it further only adds lines.
+func fitWithFont(text string, font *Font, options RenderOptions, fit FitOptions) (FitResult, bool) {
+	texts := []string{text}
+	if fit.AllowWrap {
+		texts = append(texts, wrapCandidates(text)...)
+	}
+	steps := fitSpacingSteps(options, fit.FixedSpacing)
+
+	for i, candidate := range texts {
+		candidateOptions := options
+		if i > 0 {
+			candidateOptions.CustomKerning = nil
+			candidateOptions.LineStyles = nil
+		}
+		render := func(spacing [2]int) (FitResult, bool, bool) {
+			candidateOptions.CharSpacing, candidateOptions.WordSpacing = spacing[0], spacing[1]
+			lines := RenderTextWithFont(candidate, font.FontData, candidateOptions)
+			width, height := maxStyledWidth(lines), len(lines)
+			fitsWidth := fit.Width == 0 || width <= fit.Width
+			fitsHeight := fit.Height == 0 || height <= fit.Height
+			result := FitResult{Lines: lines, Text: candidate, Font: font, Options: candidateOptions, Width: width, Height: height}
+			return result, len(lines) > 0 && fitsWidth && fitsHeight, fitsHeight
+		}
+
+		tightest, fits, fitsHeight := render(steps[len(steps)-1])
+		if !fitsHeight {
+			break
+		}
+		if !fits {
+			continue
+		}
+		for _, spacing := range steps[:len(steps)-1] {
+			if result, fits, _ := render(spacing); fits {
+				return result, true
+			}
+		}
+		return tightest, true
+	}
+	return FitResult{}, false
+}
+
+// fitSpacingSteps lists the character and word spacing pairs to try, starting
+// with the spacing of options, then narrowing the character spacing to zero,
+// then the word spacing
+func fitSpacingSteps(options RenderOptions, fixed bool) [][2]int {
+	steps := [][2]int{{options.CharSpacing, options.WordSpacing}}
+	if fixed {
+		return steps
+	}
+	for charSpacing := options.CharSpacing - 1; charSpacing >= 0; charSpacing-- {
+		steps = append(steps, [2]int{charSpacing, options.WordSpacing})
+	}
+	for wordSpacing := options.WordSpacing - 1; wordSpacing >= 0; wordSpacing-- {
+		steps = append(steps, [2]int{0, wordSpacing})
+	}
+	return steps
+}
+
+// wrapCandidates returns the distinct ways of wrapping text at spaces, from
+// the longest lines to the shortest. Each candidate wraps every line so that
+// it holds at most a given number of runes, where single words may exceed it.
+func wrapCandidates(text string) []string {
+	longest := 0
+	for _, line := range strings.Split(text, "\n") {
+		longest = max(longest, len([]rune(line)))
+	}
+
+	var candidates []string
+	previous := text
+	for limit := longest - 1; limit > 0; limit-- {
+		wrapped := wrapText(text, limit)
+		if wrapped != previous {
+			candidates = append(candidates, wrapped)
+			previous = wrapped
+		}
+	}
+	return candidates
+}
+
+// wrapText breaks every line of text at spaces so that lines hold at most
+// limit runes, keeping words longer than limit whole
+func wrapText(text string, limit int) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		words := strings.Fields(line)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		current := words[0]
+		for _, word := range words[1:] {
+			if len([]rune(current))+1+len([]rune(word)) > limit {
+				lines = append(lines, current)
+				current = word
+			} else {
+				current += " " + word
+			}
+		}
+		lines = append(lines, current)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tallestLine returns the height of the tallest text line in metrics
+func tallestLine(metrics TextMetrics) int {
+	tallest := 0
+	for _, line := range metrics.Lines {
+		tallest = max(tallest, line.Box.Height)
+	}
+	return tallest
+}
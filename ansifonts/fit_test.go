@@ -0,0 +1,72 @@
+package ansifonts
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFitText_Boundaries(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	// "Hello" in dogica is 40x7 cells at scale 1 and 80x14 at scale 2, with
+	// the spacing tightened to fit
+	tests := []struct {
+		name          string
+		fit           FitOptions
+		expectedScale float64
+	}{
+		{"exact width", FitOptions{Width: 40}, 1},
+		{"one column short", FitOptions{Width: 39}, 0.5},
+		{"exact double width", FitOptions{Width: 80}, 2},
+		{"one column short of double", FitOptions{Width: 79}, 1},
+		{"exact height", FitOptions{Height: 7}, 1},
+		{"one row short", FitOptions{Height: 6}, 0.5},
+		{"exact double height", FitOptions{Height: 14}, 2},
+		{"both limits", FitOptions{Width: 160, Height: 13}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FitText("Hello", font, DefaultRenderOptions(), tt.fit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Options.ScaleFactor != tt.expectedScale {
+				t.Errorf("expected scale %g, got %g", tt.expectedScale, result.Options.ScaleFactor)
+			}
+			if (tt.fit.Width > 0 && result.Width > tt.fit.Width) || (tt.fit.Height > 0 && result.Height > tt.fit.Height) {
+				t.Errorf("%dx%d overflows the box %dx%d", result.Width, result.Height, tt.fit.Width, tt.fit.Height)
+			}
+			if result.Width != maxStyledWidth(result.Lines) || result.Height != len(result.Lines) {
+				t.Errorf("reported size %dx%d does not match the lines", result.Width, result.Height)
+			}
+		})
+	}
+}
+
+func TestFitText_Overflow(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	tests := []struct {
+		text string
+		fit  FitOptions
+	}{
+		{"Hello", FitOptions{Width: 10}},
+		{"Hello", FitOptions{Height: 3}},
+		{"Hello world", FitOptions{Width: 40}}, // Fits only when wrapped
+	}
+	for _, tt := range tests {
+		if _, err := FitText(tt.text, font, DefaultRenderOptions(), tt.fit); !errors.Is(err, ErrNoFit) {
+			t.Errorf("%q in %dx%d: expected ErrNoFit, got %v", tt.text, tt.fit.Width, tt.fit.Height, err)
+		}
+	}
+}
+
+func TestFitText_WrapsAtSpaces(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	result, err := FitText("Hello world", font, DefaultRenderOptions(), FitOptions{Width: 60, AllowWrap: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Wrapping keeps scale 1, where one line would need half scale
+	if result.Text != "Hello\nworld" || result.Options.ScaleFactor != 1 || result.Width > 60 {
+		t.Errorf("expected two lines at scale 1 within 60 columns, got %q at %g, %d wide", result.Text, result.Options.ScaleFactor, result.Width)
+	}
+}
@@ -0,0 +1,99 @@
+package ansifonts
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultGlowRadius is the glow radius in pixels used when GlowRadius is zero
+const DefaultGlowRadius = 3
+
+// glowShades are painted from the nearest ring to the farthest
+var glowShades = []rune{'▓', '▒', '░'}
+
+// glowRadiusPixels returns the configured glow radius in pixels
+func glowRadiusPixels(options RenderOptions) int {
+	if options.GlowRadius <= 0 {
+		return DefaultGlowRadius
+	}
+	return options.GlowRadius
+}
+
+// glowCellMargins returns how far the glow reaches beyond the text block in cells.
+// A cell is one pixel wide and two pixels tall.
+func glowCellMargins(radius int) (int, int) {
+	return radius, (radius + 1) / 2
+}
+
+// glowEffect paints graded shades around the glyphs
+type glowEffect struct {
+	radius int    // Reach in pixels
+	color  string // Hex color (empty takes the text color or gradient)
+}
+
+func (e glowEffect) Bounds(width, height int) Rect {
+	marginX, marginY := glowCellMargins(e.radius)
+	return Rect{X: -marginX, Y: -marginY, Width: width + 2*marginX, Height: height + 2*marginY}
+}
+
+// Apply fills empty canvas cells around the text with graded shades. The
+// distance field is measured on the expanded binary pixel mask, so half-block
+// glyph edges glow as precisely as full blocks. Cells nearer the glyphs get
+// denser shades.
+func (e glowEffect) Apply(canvas *Canvas) {
+	plainBlock, radius := canvas.Text, e.radius
+	width := maxRowLen(plainBlock)
+	if width == 0 || radius <= 0 {
+		return
+	}
+
+	padded := make([]string, len(plainBlock))
+	for i, row := range plainBlock {
+		padded[i] = row + strings.Repeat(" ", width-utf8.RuneCountInString(row))
+	}
+	// Glyph letters glow like the blocks they fill
+	mask := ansiToExpandedBinary(lettersToBlocks(padded))
+	blockHeight := len(plainBlock)
+
+	for cy, row := range canvas.Cells {
+		for cx := range row {
+			if row[cx].Char != ' ' {
+				continue
+			}
+
+			bx, by := cx-canvas.TextX, cy-canvas.TextY
+			distance := nearestPixelDistance(mask, bx, by*2, radius)
+			distance = math.Min(distance, nearestPixelDistance(mask, bx, by*2+1, radius))
+			if distance == 0 || distance > float64(radius) {
+				// Cells covered by the glyph itself are drawn as main text later
+				continue
+			}
+
+			ring := int(math.Ceil(distance)) - 1
+			level := min(len(glowShades)-1, ring*len(glowShades)/radius)
+			row[cx] = Cell{
+				Char:   glowShades[level],
+				Color:  e.color,
+				Layer:  GlowLayer,
+				Row:    clamp(by, 0, blockHeight-1),
+				Column: bx,
+			}
+		}
+	}
+}
+
+// nearestPixelDistance returns the Euclidean distance from pixel (px, py) to
+// the nearest lit pixel within radius, or +Inf when there is none
+func nearestPixelDistance(mask [][]int, px, py, radius int) float64 {
+	best := math.Inf(1)
+	for y := max(0, py-radius); y <= py+radius && y < len(mask); y++ {
+		for x := max(0, px-radius); x <= px+radius && x < len(mask[y]); x++ {
+			if mask[y][x] == 1 {
+				dx, dy := float64(x-px), float64(y-py)
+				best = math.Min(best, math.Sqrt(dx*dx+dy*dy))
+			}
+		}
+	}
+	return best
+}
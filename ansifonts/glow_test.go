@@ -0,0 +1,98 @@
+package ansifonts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// paintCanvas runs effects on a canvas around block, as applyStylingAndShadow does
+func paintCanvas(block []string, effects []Effect) *Canvas {
+	minX, maxX, minY, maxY := effectBounds(maxRowLen(block), len(block), effects)
+	canvas := &Canvas{Cells: make([][]Cell, maxY-minY), Text: block, TextX: -minX, TextY: -minY}
+	for y := range canvas.Cells {
+		canvas.Cells[y] = make([]Cell, maxX-minX)
+		for x := range canvas.Cells[y] {
+			canvas.Cells[y][x] = Cell{Char: ' '}
+		}
+	}
+	for _, effect := range effects {
+		effect.Apply(canvas)
+	}
+	return canvas
+}
+
+// canvasChars returns the characters of the canvas rows
+func canvasChars(canvas *Canvas) []string {
+	rows := make([]string, len(canvas.Cells))
+	for y, row := range canvas.Cells {
+		var builder strings.Builder
+		for _, cell := range row {
+			builder.WriteRune(cell.Char)
+		}
+		rows[y] = builder.String()
+	}
+	return rows
+}
+
+func TestGlowEffect_Falloff(t *testing.T) {
+	// Along the row of a single full block the glow reaches radius pixels,
+	// one pixel per cell, with denser shades nearer the glyph
+	tests := []struct {
+		radius   int
+		expected string
+	}{
+		{1, "▓█▓"},
+		{2, "▒▓█▓▒"},
+		{3, "░▒▓█▓▒░"},
+		{6, "░░▒▒▓▓█▓▓▒▒░░"},
+	}
+	for _, tt := range tests {
+		canvas := paintCanvas([]string{"█"}, []Effect{glowEffect{radius: tt.radius}, textEffect{}})
+		if got := canvasChars(canvas)[canvas.TextY]; got != tt.expected {
+			t.Errorf("radius %d: expected %q, got %q", tt.radius, tt.expected, got)
+		}
+	}
+}
+
+func TestGlowEffect_DistanceField(t *testing.T) {
+	// Cells are two pixels tall, so the glow reaches (radius+1)/2 rows up and
+	// down, and diagonal cells are farther away than their row and column
+	canvas := paintCanvas([]string{"█"}, []Effect{glowEffect{radius: 2}, textEffect{}})
+	expected := []string{
+		" ▒▓▒ ",
+		"▒▓█▓▒",
+		" ▒▓▒ ",
+	}
+	if got := canvasChars(canvas); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%s\ngot:\n%s", joinLines(expected), joinLines(got))
+	}
+
+	// A lower half block glows from its lit pixel only
+	canvas = paintCanvas([]string{"▄"}, []Effect{glowEffect{radius: 1}, textEffect{}})
+	expected = []string{
+		"   ",
+		"▓▄▓",
+		" ▓ ",
+	}
+	if got := canvasChars(canvas); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%s\ngot:\n%s", joinLines(expected), joinLines(got))
+	}
+}
+
+func TestGlowEffect_BelowOtherLayers(t *testing.T) {
+	// The glow shows left of the glyphs, where nothing else is painted, and
+	// the glyphs and their shadow cover it everywhere else
+	options := DefaultRenderOptions()
+	options.GlowEnabled, options.GlowRadius = true, 1
+	options.ShadowEnabled, options.ShadowHorizontalOffset = true, 1
+	canvas := paintCanvas([]string{"█ █"}, styleEffects(options))
+
+	row := canvas.Cells[canvas.TextY]
+	expectedLayers := []Layer{GlowLayer, TextLayer, ShadowLayer, TextLayer, ShadowLayer}
+	for i, expected := range expectedLayers {
+		if cell := row[canvas.TextX-1+i]; cell.Layer != expected || cell.Char == ' ' {
+			t.Errorf("cell %d: expected a %d layer cell, got %q on layer %d", i, expected, cell.Char, cell.Layer)
+		}
+	}
+}
@@ -8,7 +8,8 @@ import "strings"
 //
 // The inline syntax uses \+ to add 1 pixel of space and \- to remove 1 pixel
 // of space before the next character. Multiple markers stack, so \+\+ adds
-// 2 pixels and \-\- removes 2 pixels.
+// 2 pixels and \-\- removes 2 pixels. A doubled backslash \\ stands for a
+// literal backslash, so \\+ renders a backslash followed by a plus sign.
 //
 // For example, the input "H\+el\+lo W\-orld" produces the clean text
 // "Hello World" and the kerning map {0: {1: 1, 3: 1, 7: -1}}.
@@ -30,7 +31,12 @@ func ParseInlineKerning(text string) (string, map[int]map[int]int) {
 		for i < len(runes) {
 			// Detect inline kerning markers: \+ (add space) and \- (remove space)
 			if runes[i] == '\\' && i+1 < len(runes) {
-				if runes[i+1] == '+' {
+				if runes[i+1] == '\\' {
+					cleanRunes = append(cleanRunes, '\\')
+					cleanIdx++
+					i += 2
+					continue
+				} else if runes[i+1] == '+' {
 					if kerning[lineIdx] == nil {
 						kerning[lineIdx] = make(map[int]int)
 					}
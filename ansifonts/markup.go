@@ -0,0 +1,319 @@
+package ansifonts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MarkupSpan is a run of markup text that shares one set of attributes
+type MarkupSpan struct {
+	Text       string            // Span text, which may contain newlines
+	Attributes map[string]string // Attributes of all open tags, inner tags overriding outer ones
+	Kerning    map[int]int       // Inline kerning adjustments keyed by rune index in Text
+}
+
+// MarkupError describes a syntax or attribute error in markup text
+type MarkupError struct {
+	Position int // Rune offset of the problem in the markup
+	Message  string
+}
+
+func (e *MarkupError) Error() string {
+	return fmt.Sprintf("markup error at position %d: %s", e.Position, e.Message)
+}
+
+// markupAttributes lists the attributes a markup tag accepts
+var markupAttributes = map[string]bool{
+	"color":     true,
+	"gradient":  true,
+	"direction": true,
+	"font":      true,
+	"scale":     true,
+	"shadow":    true,
+	"bold":      true,
+}
+
+// HasMarkup reports whether text contains an unescaped markup tag
+func HasMarkup(text string) bool {
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '\\' {
+			i++
+			continue
+		}
+		if runes[i] == '{' {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMarkup splits text with inline markup into styled spans.
+//
+// A tag such as {color=#f00 font=dogica scale=2} opens a span and {/} closes
+// the most recent one. Tags nest, and inner attributes override outer ones.
+// Supported attributes are color and gradient (hex, #rgb shorthand, ANSI code,
+// or "none" for gradient), direction (down, up, right, left), font (a font
+// name), scale (0.5, 1, 2 or 4), shadow (on, off, or "h,v" offsets) and bold
+// (0 to 3).
+//
+// The inline kerning markers \+ and \- work as in ParseInlineKerning. A
+// backslash escapes {, } and itself, so \{ \} and \\ produce literal
+// characters. Any other backslash is kept as is.
+func ParseMarkup(markup string) ([]MarkupSpan, error) {
+	runes := []rune(markup)
+	var spans []MarkupSpan
+	var stack []map[string]string
+	current := MarkupSpan{Attributes: map[string]string{}}
+	var text []rune
+
+	flush := func() {
+		if len(text) > 0 || len(current.Kerning) > 0 {
+			current.Text = string(text)
+			spans = append(spans, current)
+		}
+		attributes := map[string]string{}
+		for _, frame := range stack {
+			for key, value := range frame {
+				attributes[key] = value
+			}
+		}
+		current = MarkupSpan{Attributes: attributes}
+		text = nil
+	}
+
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				switch runes[i+1] {
+				case '{', '}', '\\':
+					text = append(text, runes[i+1])
+					i++
+					continue
+				case '+', '-':
+					if current.Kerning == nil {
+						current.Kerning = make(map[int]int)
+					}
+					if runes[i+1] == '+' {
+						current.Kerning[len(text)]++
+					} else {
+						current.Kerning[len(text)]--
+					}
+					i++
+					continue
+				}
+			}
+			text = append(text, '\\')
+		case '{':
+			end := i + 1
+			for end < len(runes) && runes[end] != '}' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, &MarkupError{Position: i, Message: "unterminated tag"}
+			}
+			tag := strings.TrimSpace(string(runes[i+1 : end]))
+			if tag == "/" {
+				if len(stack) == 0 {
+					return nil, &MarkupError{Position: i, Message: "closing tag without an open tag"}
+				}
+				flush()
+				stack = stack[:len(stack)-1]
+				flush()
+			} else {
+				attributes, err := parseMarkupTag(tag)
+				if err != nil {
+					return nil, &MarkupError{Position: i, Message: err.Error()}
+				}
+				flush()
+				stack = append(stack, attributes)
+				flush()
+			}
+			i = end
+		case '}':
+			return nil, &MarkupError{Position: i, Message: "unexpected } (write \\} for a literal brace)"}
+		default:
+			text = append(text, runes[i])
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, &MarkupError{Position: len(runes), Message: fmt.Sprintf("%d unclosed tag(s)", len(stack))}
+	}
+	flush()
+	return spans, nil
+}
+
+// MarkupText returns the text of markup without its tags, escapes and inline
+// kerning markers
+func MarkupText(markup string) (string, error) {
+	spans, err := ParseMarkup(markup)
+	if err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	for _, span := range spans {
+		text.WriteString(span.Text)
+	}
+	return text.String(), nil
+}
+
+// parseMarkupTag parses the key=value pairs of an opening tag and checks that
+// every value is usable, so errors surface with their position
+func parseMarkupTag(tag string) (map[string]string, error) {
+	fields := strings.Fields(tag)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty tag")
+	}
+
+	attributes := make(map[string]string, len(fields))
+	for _, field := range fields {
+		key, value, found := strings.Cut(field, "=")
+		if !found || value == "" {
+			return nil, fmt.Errorf("attribute %q must look like key=value", field)
+		}
+		key = strings.ToLower(key)
+		if !markupAttributes[key] {
+			return nil, fmt.Errorf("unknown attribute %q", key)
+		}
+		if key != "font" {
+			var scratch RenderOptions
+			if err := applyMarkupAttribute(&scratch, key, value); err != nil {
+				return nil, err
+			}
+		}
+		attributes[key] = value
+	}
+	return attributes, nil
+}
+
+// applyMarkupAttribute sets the render option that a markup attribute controls.
+// The font attribute is resolved by the caller.
+func applyMarkupAttribute(options *RenderOptions, key, value string) error {
+	switch key {
+	case "color":
+		hex, err := parseMarkupColor(value)
+		if err != nil {
+			return err
+		}
+		options.TextColor = hex
+	case "gradient":
+		if value == "none" {
+			options.UseGradient = false
+			return nil
+		}
+		hex, err := parseMarkupColor(value)
+		if err != nil {
+			return err
+		}
+		options.GradientColor = hex
+		options.UseGradient = true
+	case "direction":
+		directions := map[string]GradientDirection{"down": UpDown, "up": DownUp, "right": LeftRight, "left": RightLeft}
+		direction, ok := directions[value]
+		if !ok {
+			return fmt.Errorf("invalid direction %q (use down, up, right or left)", value)
+		}
+		options.GradientDirection = direction
+	case "scale":
+		scale, err := strconv.ParseFloat(value, 64)
+		if err != nil || (scale != 0.5 && scale != 1 && scale != 2 && scale != 4) {
+			return fmt.Errorf("invalid scale %q (use 0.5, 1, 2 or 4)", value)
+		}
+		options.ScaleFactor = scale
+	case "shadow":
+		switch value {
+		case "on":
+			options.ShadowEnabled = true
+			if options.ShadowHorizontalOffset == 0 && options.ShadowVerticalOffset == 0 {
+				options.ShadowHorizontalOffset, options.ShadowVerticalOffset = 1, 1
+			}
+		case "off":
+			options.ShadowEnabled = false
+		default:
+			h, v, found := strings.Cut(value, ",")
+			horizontal, errH := strconv.Atoi(h)
+			vertical, errV := strconv.Atoi(v)
+			if !found || errH != nil || errV != nil ||
+				horizontal < MinShadowOffset || horizontal > MaxShadowOffset ||
+				vertical < MinShadowOffset || vertical > MaxShadowOffset {
+				return fmt.Errorf("invalid shadow %q (use on, off or h,v offsets from %d to %d)", value, MinShadowOffset, MaxShadowOffset)
+			}
+			options.ShadowEnabled = true
+			options.ShadowHorizontalOffset, options.ShadowVerticalOffset = horizontal, vertical
+		}
+	case "bold":
+		strength, err := strconv.Atoi(value)
+		if err != nil || strength < MinBoldStrength || strength > MaxBoldStrength {
+			return fmt.Errorf("invalid bold %q (use %d to %d)", value, MinBoldStrength, MaxBoldStrength)
+		}
+		options.BoldStrength = strength
+	}
+	return nil
+}
+
+// parseMarkupColor converts a hex color, a #rgb shorthand or an ANSI code to hex
+func parseMarkupColor(value string) (string, error) {
+	if hex, ok := ANSIColorMap[value]; ok {
+		return hex, nil
+	}
+	if len(value) == 4 && value[0] == '#' {
+		value = string([]byte{'#', value[1], value[1], value[2], value[2], value[3], value[3]})
+	}
+	if !isValidHexColor(value) {
+		return "", fmt.Errorf("invalid color %q (use #RRGGBB, #RGB or an ANSI code)", value)
+	}
+	return strings.ToUpper(value), nil
+}
+
+// RenderMarkup renders text containing inline markup (see ParseMarkup).
+// Spans without a font attribute use fontData, and font attributes load fonts
+// by name with LoadFont. Each span becomes a TextRun of ComposeRuns, so spans
+// of different fonts and scales share a common baseline. Layout transforms and
+// reflections do not apply to markup, while the background, padding and frame
+// of options wrap the whole result.
+func RenderMarkup(markup string, fontData FontData, options RenderOptions) ([]string, error) {
+	spans, err := ParseMarkup(markup)
+	if err != nil {
+		return nil, err
+	}
+
+	fonts := map[string]*Font{}
+	runs := make([]TextRun, 0, len(spans))
+	for _, span := range spans {
+		spanOptions := options
+		_, hasColor := span.Attributes["color"]
+		_, hasGradient := span.Attributes["gradient"]
+		if hasColor {
+			// A span color replaces an inherited gradient unless the span sets its own
+			spanOptions.UseGradient = false
+		}
+		if hasColor || hasGradient {
+			// Span colors also win over an inherited palette
+			spanOptions.PaletteMode = NoPalette
+		}
+		for key, value := range span.Attributes {
+			if key == "font" {
+				continue
+			}
+			if err := applyMarkupAttribute(&spanOptions, key, value); err != nil {
+				return nil, err
+			}
+		}
+		run := TextRun{Text: span.Text, Options: &spanOptions, Kerning: span.Kerning}
+		if name, ok := span.Attributes["font"]; ok {
+			if _, loaded := fonts[name]; !loaded {
+				font, err := LoadFont(name)
+				if err != nil {
+					return nil, fmt.Errorf("markup font %q: %w", name, err)
+				}
+				fonts[name] = font
+			}
+			run.Font = fonts[name]
+		}
+		runs = append(runs, run)
+	}
+	return ComposeRuns(runs, fontData, options), nil
+}
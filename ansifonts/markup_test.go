@@ -0,0 +1,87 @@
+package ansifonts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMarkup(t *testing.T) {
+	tests := []struct {
+		name     string
+		markup   string
+		expected []MarkupSpan
+		err      string // Substring of the expected error, empty when parsing succeeds
+	}{
+		{
+			name:   "plain text",
+			markup: "Hello",
+			expected: []MarkupSpan{
+				{Text: "Hello", Attributes: map[string]string{}},
+			},
+		},
+		{
+			name:   "nested tags",
+			markup: "a{color=#f00 scale=2}b{color=#00f}c{/}d{/}e",
+			expected: []MarkupSpan{
+				{Text: "a", Attributes: map[string]string{}},
+				{Text: "b", Attributes: map[string]string{"color": "#f00", "scale": "2"}},
+				{Text: "c", Attributes: map[string]string{"color": "#00f", "scale": "2"}},
+				{Text: "d", Attributes: map[string]string{"color": "#f00", "scale": "2"}},
+				{Text: "e", Attributes: map[string]string{}},
+			},
+		},
+		{
+			name:   "escaped braces and backslash",
+			markup: `\{a\}\\b\n`,
+			expected: []MarkupSpan{
+				{Text: `{a}\b\n`, Attributes: map[string]string{}},
+			},
+		},
+		{
+			name:   "kerning markers",
+			markup: `A\-\-B{bold=1}C\+D{/}`,
+			expected: []MarkupSpan{
+				{Text: "AB", Attributes: map[string]string{}, Kerning: map[int]int{1: -2}},
+				{Text: "CD", Attributes: map[string]string{"bold": "1"}, Kerning: map[int]int{1: 1}},
+			},
+		},
+		{name: "unknown attribute", markup: "{size=2}a{/}", err: `unknown attribute "size"`},
+		{name: "invalid value", markup: "{color=nope}a{/}", err: "nope"},
+		{name: "unsupported scale", markup: "{scale=3}a{/}", err: `invalid scale "3" (use 0.5, 1, 2 or 4)`},
+		{name: "attribute without value", markup: "{color}a{/}", err: "key=value"},
+		{name: "empty tag", markup: "{}a", err: "empty tag"},
+		{name: "unclosed tag", markup: "{color=#f00}a", err: "1 unclosed tag(s)"},
+		{name: "closing tag without open tag", markup: "a{/}", err: "closing tag without an open tag"},
+		{name: "unterminated tag", markup: "a{color=#f00", err: "unterminated tag"},
+		{name: "stray closing brace", markup: "a}b", err: "unexpected }"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans, err := ParseMarkup(tt.markup)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(spans, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, spans)
+			}
+		})
+	}
+}
+
+func TestMarkupText(t *testing.T) {
+	text, err := MarkupText(`{color=#f00}Hi{/} {bold=1}\{there\}\+{/}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "Hi {there}" {
+		t.Errorf("expected %q, got %q", "Hi {there}", text)
+	}
+}
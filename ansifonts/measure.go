@@ -0,0 +1,174 @@
+package ansifonts
+
+import "strings"
+
+// Rect is a rectangle of character cells
+type Rect struct {
+	X, Y          int // Top-left cell
+	Width, Height int
+}
+
+// CharMetrics describes where one character of the text lands
+type CharMetrics struct {
+	Rune  rune
+	Index int     // Rune index within its text line
+	X     float64 // Start column of the character's advance, with half-cell precision
+	Box   Rect    // Cells drawn by the glyph; empty for spaces and blank glyphs
+}
+
+// LineMetrics describes one text line of a render
+type LineMetrics struct {
+	Box      Rect // Cells drawn by the glyphs of the line, without shadows or other effects
+	Baseline int  // Row on which letters without descenders rest, -1 when the text is not upright
+	Descent  int  // Rows that descenders reach below the baseline
+	Chars    []CharMetrics
+}
+
+// TextMetrics describes the layout of rendered text. All positions are cells
+// of the rendered block, counted from its top-left corner.
+type TextMetrics struct {
+	Width  int // Width of the rendered block, including effects and decoration
+	Height int // Height of the rendered block, including effects and decoration
+	Lines  []LineMetrics
+}
+
+// MeasureText returns the size of the block RenderTextWithOptions draws for
+// text, together with the boxes of its lines and characters and the baseline
+// and descent of each line.
+//
+// In vertical, rotated and mirrored layouts the character X is the left edge
+// of its box, characters without pixels report an X of -1, and lines that are
+// not upright report a Baseline of -1.
+func MeasureText(text string, font *Font, options RenderOptions) TextMetrics {
+	if font == nil {
+		return TextMetrics{}
+	}
+	return measureTextWithFont(text, font.FontData, options)
+}
+
+func measureTextWithFont(text string, fontData FontData, options RenderOptions) TextMetrics {
+	rendered := RenderTextWithFont(text, fontData, options)
+	metrics := TextMetrics{Width: maxStyledWidth(rendered), Height: len(rendered)}
+	if len(rendered) == 0 {
+		return metrics
+	}
+
+	// Follow the same steps as RenderTextWithFont, keeping track of positions
+	options = disableHalfPixelShadows(text, fontData, options)
+	textLines := strings.Split(text, "\n")
+	layouts, maxTextLineWidth := layoutTextLines(textLines, fontData, options)
+	decorationX, decorationY := decorationOffset(options)
+	if composesWholeBlock(options) {
+		metrics.Lines = measureTransformedLines(textLines, layouts, fontData, options, decorationX, decorationY)
+		return metrics
+	}
+
+	y := decorationY
+	for i, layout := range layouts {
+		line := LineMetrics{Baseline: -1}
+		if layout.rows == nil {
+			line.Box = Rect{X: decorationX, Y: y}
+			if i > 0 {
+				y++
+			}
+			metrics.Lines = append(metrics.Lines, line)
+			continue
+		}
+		if i > 0 && y > decorationY {
+			y += options.LineSpacing
+		}
+
+		lineWidth := maxRowLen(layout.rows)
+		minX, _, minY, maxY := canvasBounds(maxTextLineWidth, len(layout.rows), layout.options)
+		originX := decorationX - minX + alignmentPadding(lineWidth, maxTextLineWidth, layout.options.Alignment)
+		originY := y - minY
+
+		line.Box = Rect{X: originX, Y: originY, Width: lineWidth, Height: len(layout.rows)}
+		line.Baseline, line.Descent = lineBaseline(layout)
+		line.Baseline += originY
+		boxes := charBoxes(layout.sources, originX, originY)
+		for index, r := range []rune(textLines[i]) {
+			char := CharMetrics{Rune: r, Index: index, Box: boxes[index+1]}
+			if index < len(layout.positions) {
+				char.X = float64(originX) + layout.positions[index]
+			}
+			line.Chars = append(line.Chars, char)
+		}
+		metrics.Lines = append(metrics.Lines, line)
+		y += maxY - minY
+	}
+	return metrics
+}
+
+// measureTransformedLines measures text laid out as a whole block, following
+// every character through the layout transforms by its source
+func measureTransformedLines(textLines []string, layouts []textLineLayout, fontData FontData, options RenderOptions, decorationX, decorationY int) []LineMetrics {
+	slots := paletteSlots(textLines, PalettePerChar)
+	plainBlock, sources := renderPlainBlock(textLines, fontData, options, slots)
+	minX, _, minY, _ := canvasBounds(maxRowLen(plainBlock), len(plainBlock), options)
+	boxes := charBoxes(sources, decorationX-minX, decorationY-minY)
+	upright := !options.VerticalLayout && options.Rotation == NoRotation && !options.FlipVertical
+
+	lines := make([]LineMetrics, len(textLines))
+	for i, textLine := range textLines {
+		line := LineMetrics{Baseline: -1}
+		for index, r := range []rune(textLine) {
+			char := CharMetrics{Rune: r, Index: index, X: -1}
+			if slot := slots[i][index]; slot >= 0 {
+				char.Box = boxes[slot+1]
+			}
+			if char.Box.Width > 0 {
+				char.X = float64(char.Box.X)
+				line.Box = unionRect(line.Box, char.Box)
+			}
+			line.Chars = append(line.Chars, char)
+		}
+		if upright && layouts[i].rows != nil && line.Box.Height > 0 {
+			line.Baseline, line.Descent = lineBaseline(layouts[i])
+			line.Baseline += line.Box.Y
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// lineBaseline returns the baseline of a laid out line, counted from its top
+// row with ink, and how many rows its descenders reach below it
+func lineBaseline(layout textLineLayout) (int, int) {
+	untrimmed := append(make([]string, layout.top), layout.rows...)
+	baseline := runBaselineRow(layout.fontData, layout.options, untrimmed)
+	descent := max(0, layout.top+len(layout.rows)-1-baseline)
+	return baseline - layout.top, descent
+}
+
+// charBoxes returns the cells covered by every source of a source map, keyed
+// by source, with the map's top-left cell placed at (originX, originY)
+func charBoxes(sources sourceMap, originX, originY int) map[int]Rect {
+	boxes := make(map[int]Rect)
+	for y, row := range sources {
+		for x, source := range row {
+			if source > 0 {
+				boxes[source] = unionRect(boxes[source], Rect{X: originX + x, Y: originY + y, Width: 1, Height: 1})
+			}
+		}
+	}
+	return boxes
+}
+
+// unionRect returns the smallest rectangle containing both rectangles, where
+// an empty rectangle adds nothing
+func unionRect(a, b Rect) Rect {
+	if a.Width == 0 || a.Height == 0 {
+		return b
+	}
+	if b.Width == 0 || b.Height == 0 {
+		return a
+	}
+	x, y := min(a.X, b.X), min(a.Y, b.Y)
+	return Rect{
+		X:      x,
+		Y:      y,
+		Width:  max(a.X+a.Width, b.X+b.Width) - x,
+		Height: max(a.Y+a.Height, b.Y+b.Height) - y,
+	}
+}
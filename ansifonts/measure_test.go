@@ -0,0 +1,62 @@
+package ansifonts
+
+import "testing"
+
+func TestMeasureText_MultiLine(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	options := DefaultRenderOptions()
+	text := "Hi\nok g"
+
+	metrics := MeasureText(text, font, options)
+	rendered := RenderTextWithOptions(text, font, options)
+	if metrics.Width != maxStyledWidth(rendered) || metrics.Height != len(rendered) {
+		t.Errorf("expected size %dx%d, got %dx%d", maxStyledWidth(rendered), len(rendered), metrics.Width, metrics.Height)
+	}
+	if len(metrics.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(metrics.Lines))
+	}
+
+	// The centered first line sits above the second, one line spacing apart,
+	// and the descender of "g" reaches one row below the second baseline
+	expected := []struct {
+		box               Rect
+		baseline, descent int
+	}{
+		{Rect{X: 8, Y: 0, Width: 17, Height: 7}, 6, 0},
+		{Rect{X: 0, Y: 8, Width: 33, Height: 7}, 13, 1},
+	}
+	for i, want := range expected {
+		line := metrics.Lines[i]
+		if line.Box != want.box || line.Baseline != want.baseline || line.Descent != want.descent {
+			t.Errorf("line %d: expected box %+v baseline %d descent %d, got box %+v baseline %d descent %d",
+				i, want.box, want.baseline, want.descent, line.Box, line.Baseline, line.Descent)
+		}
+	}
+
+	chars := metrics.Lines[1].Chars
+	if len(chars) != 4 {
+		t.Fatalf("expected 4 characters on the second line, got %d", len(chars))
+	}
+	if space := chars[2]; space.Rune != ' ' || space.Box != (Rect{}) {
+		t.Errorf("expected an empty box for the space, got %+v", space)
+	}
+	if g := chars[3]; g.Index != 3 || g.Box.Y+g.Box.Height-1 != 13+1 {
+		t.Errorf("expected the descender of g to end one row below the baseline, got %+v", g)
+	}
+}
+
+func TestMeasureText_IncludesEffects(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	options := DefaultRenderOptions()
+	plain := MeasureText("Hi", font, options)
+
+	options.ShadowEnabled, options.ShadowHorizontalOffset, options.ShadowVerticalOffset = true, 2, 1
+	shadowed := MeasureText("Hi", font, options)
+	if shadowed.Width != plain.Width+2 || shadowed.Height != plain.Height+1 {
+		t.Errorf("expected the shadow to add 2x1 cells to %dx%d, got %dx%d", plain.Width, plain.Height, shadowed.Width, shadowed.Height)
+	}
+	// Glyph boxes leave the shadow out
+	if shadowed.Lines[0].Box != plain.Lines[0].Box {
+		t.Errorf("expected line box %+v, got %+v", plain.Lines[0].Box, shadowed.Lines[0].Box)
+	}
+}
@@ -0,0 +1,166 @@
+package ansifonts
+
+import (
+	"math/rand/v2"
+	"unicode"
+)
+
+// RainbowPalette returns the default palette used when Palette is empty
+func RainbowPalette() []string {
+	return []string{"#FF0000", "#FF8000", "#FFFF00", "#00FF00", "#00BFFF", "#4B6BFF", "#BF40FF"}
+}
+
+// paletteSlots numbers the characters or words of the text across all lines,
+// returning the slot of every rune of every line. Spaces get -1.
+func paletteSlots(textLines []string, mode PaletteMode) [][]int {
+	slots := make([][]int, len(textLines))
+	next := 0
+	for lineIndex, line := range textLines {
+		runes := []rune(line)
+		slots[lineIndex] = make([]int, len(runes))
+		for i, r := range runes {
+			if unicode.IsSpace(r) {
+				slots[lineIndex][i] = -1
+				continue
+			}
+			if mode == PalettePerWord && i > 0 && !unicode.IsSpace(runes[i-1]) {
+				// Letters after the first one continue the current word
+				slots[lineIndex][i] = next - 1
+				continue
+			}
+			slots[lineIndex][i] = next
+			next++
+		}
+	}
+	return slots
+}
+
+// paletteColor returns the palette color of a slot. Random order draws from a
+// generator seeded with PaletteSeed and the slot, so a seed always gives the
+// same colors.
+func paletteColor(options RenderOptions, slot int) string {
+	palette := options.Palette
+	if len(palette) == 0 {
+		palette = RainbowPalette()
+	}
+	if options.PaletteOrder == PaletteRandom {
+		generator := rand.New(rand.NewPCG(options.PaletteSeed, uint64(slot)))
+		return palette[generator.IntN(len(palette))]
+	}
+	return palette[slot%len(palette)]
+}
+
+// sourceMap records, for every cell of a plain block, where the cell came from.
+// renderTextWithSources fills it with rune indices within a text line, and
+// toSlots turns those into palette slots. Values are one-based so that 0 marks
+// a cell without a source. A nil map means sources are not tracked, and every
+// method returns nil for it.
+type sourceMap [][]int
+
+// at returns the value of cell (x, y), or 0 outside the map
+func (m sourceMap) at(x, y int) int {
+	if y < 0 || y >= len(m) || x < 0 || x >= len(m[y]) {
+		return 0
+	}
+	return m[y][x]
+}
+
+// toSlots converts rune indices of one text line into palette slots
+func (m sourceMap) toSlots(lineSlots []int) sourceMap {
+	if m == nil || lineSlots == nil {
+		return nil
+	}
+	result := make(sourceMap, len(m))
+	for y, row := range m {
+		result[y] = make([]int, len(row))
+		for x, source := range row {
+			if source > 0 && lineSlots[source-1] >= 0 {
+				result[y][x] = lineSlots[source-1] + 1
+			}
+		}
+	}
+	return result
+}
+
+// shiftRight moves every row right by n cells
+func (m sourceMap) shiftRight(n int) sourceMap {
+	if m == nil || n <= 0 {
+		return m
+	}
+	result := make(sourceMap, len(m))
+	for y, row := range m {
+		result[y] = append(make([]int, n), row...)
+	}
+	return result
+}
+
+// mirrorHorizontally reverses each row of a map padded to the given width
+func (m sourceMap) mirrorHorizontally(width int) sourceMap {
+	if m == nil {
+		return nil
+	}
+	result := make(sourceMap, len(m))
+	for y := range m {
+		result[y] = make([]int, width)
+		for x := range width {
+			result[y][x] = m.at(width-1-x, y)
+		}
+	}
+	return result
+}
+
+// mirrorVertically reverses the row order
+func (m sourceMap) mirrorVertically() sourceMap {
+	if m == nil {
+		return nil
+	}
+	result := make(sourceMap, len(m))
+	for y, row := range m {
+		result[len(m)-1-y] = row
+	}
+	return result
+}
+
+// rotate turns the map a quarter turn the same way transformBlock turns the
+// pixels: every cell becomes two pixel rows, the pixel grid is rotated, and
+// each resulting cell takes the source of its upper pixel, or the lower one
+// when the upper pixel has none.
+func (m sourceMap) rotate(clockwise bool, width int) sourceMap {
+	if m == nil {
+		return nil
+	}
+	pixels := make([][]int, len(m)*2)
+	for y := range m {
+		row := make([]int, width)
+		for x := range width {
+			row[x] = m.at(x, y)
+		}
+		pixels[y*2], pixels[y*2+1] = row, row
+	}
+	if clockwise {
+		pixels = rotateBitmapClockwise(pixels)
+	} else {
+		pixels = rotateBitmapCounterClockwise(pixels)
+	}
+
+	result := make(sourceMap, (len(pixels)+1)/2)
+	for y := range result {
+		top := pixels[y*2]
+		result[y] = make([]int, len(top))
+		for x := range top {
+			result[y][x] = top[x]
+			if result[y][x] == 0 && y*2+1 < len(pixels) {
+				result[y][x] = pixels[y*2+1][x]
+			}
+		}
+	}
+	return result
+}
+
+// cut keeps the rows from start up to end, as emptyLineBounds returns them
+func (m sourceMap) cut(start, end int) sourceMap {
+	if m == nil || end > len(m) {
+		return nil
+	}
+	return m[start:end]
+}
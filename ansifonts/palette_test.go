@@ -0,0 +1,118 @@
+package ansifonts
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestPaletteSlots(t *testing.T) {
+	textLines := []string{"ab cd", "e  f"}
+	tests := []struct {
+		mode     PaletteMode
+		expected [][]int
+	}{
+		{PalettePerChar, [][]int{{0, 1, -1, 2, 3}, {4, -1, -1, 5}}},
+		{PalettePerWord, [][]int{{0, 0, -1, 1, 1}, {2, -1, -1, 3}}},
+	}
+	for _, tt := range tests {
+		if got := paletteSlots(textLines, tt.mode); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("mode %d: expected %v, got %v", tt.mode, tt.expected, got)
+		}
+	}
+}
+
+func TestPaletteColor_Cycle(t *testing.T) {
+	options := DefaultRenderOptions()
+	options.Palette = []string{"#FF0000", "#00FF00", "#0000FF"}
+	var got []string
+	for slot := range 5 {
+		got = append(got, paletteColor(options, slot))
+	}
+	expected := []string{"#FF0000", "#00FF00", "#0000FF", "#FF0000", "#00FF00"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	options.Palette = nil
+	if got := paletteColor(options, 8); got != RainbowPalette()[1] {
+		t.Errorf("expected the rainbow palette without a palette, got %s", got)
+	}
+}
+
+func TestPaletteColor_RandomSeed(t *testing.T) {
+	options := DefaultRenderOptions()
+	options.PaletteOrder = PaletteRandom
+	colors := func(seed uint64) []string {
+		options.PaletteSeed = seed
+		var result []string
+		for slot := range 20 {
+			result = append(result, paletteColor(options, slot))
+		}
+		return result
+	}
+
+	first := colors(7)
+	if again := colors(7); !reflect.DeepEqual(first, again) {
+		t.Errorf("expected the same seed to give the same colors, got %v and %v", first, again)
+	}
+	if other := colors(8); reflect.DeepEqual(first, other) {
+		t.Errorf("expected another seed to give other colors, got %v for both", first)
+	}
+	for _, color := range first {
+		if !slices.Contains(RainbowPalette(), color) {
+			t.Errorf("expected palette colors only, got %s", color)
+		}
+	}
+}
+
+func TestPalette_ColorsFollowTheText(t *testing.T) {
+	font := &Font{Name: "tiny", FontData: tinyFont}
+	const red, green = "255;0;0", "0;255;0"
+	tests := []struct {
+		name     string
+		text     string
+		options  func(*RenderOptions)
+		expected [][]string
+	}{
+		{"per character", "AB", func(o *RenderOptions) {}, [][]string{{red, green}, {red, green}}},
+		{"per word", "AB A", func(o *RenderOptions) { o.PaletteMode = PalettePerWord }, [][]string{{red, green}, {red, green}}},
+		{"per word, one word", "AB", func(o *RenderOptions) { o.PaletteMode = PalettePerWord }, [][]string{{red}, {red}}},
+		{"flipped", "AB", func(o *RenderOptions) { o.FlipHorizontal = true }, [][]string{{green, red}, {green, red}}},
+		{"mirrored upside down", "AB", func(o *RenderOptions) { o.FlipVertical = true }, [][]string{{red, green}, {red, green}}},
+		// A quarter turn puts A above B
+		{"rotated", "AB", func(o *RenderOptions) { o.Rotation = Rotate90 }, [][]string{{red}, {red}, {green}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultRenderOptions()
+			options.PaletteMode = PalettePerChar
+			options.Palette = []string{"#FF0000", "#00FF00"}
+			tt.options(&options)
+			if got := rowColors(RenderTextWithOptions(tt.text, font, options)); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected row colors %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSourceMap_Transforms(t *testing.T) {
+	sources := sourceMap{{1, 2}, {3}}
+	if got := sources.mirrorHorizontally(2); !reflect.DeepEqual(got, sourceMap{{2, 1}, {0, 3}}) {
+		t.Errorf("mirrorHorizontally: got %v", got)
+	}
+	if got := sources.mirrorVertically(); !reflect.DeepEqual(got, sourceMap{{3}, {1, 2}}) {
+		t.Errorf("mirrorVertically: got %v", got)
+	}
+	// Each cell becomes two pixel rows, and a rotated cell takes the source
+	// of its upper pixel
+	if got := sources.rotate(true, 2); !reflect.DeepEqual(got, sourceMap{{3, 3, 1, 1}}) {
+		t.Errorf("rotate clockwise: got %v", got)
+	}
+	if got := sources.rotate(false, 2); !reflect.DeepEqual(got, sourceMap{{2, 2, 3, 3}}) {
+		t.Errorf("rotate counterclockwise: got %v", got)
+	}
+	if got := sourceMap(nil).rotate(true, 2); got != nil {
+		t.Errorf("expected a nil map to stay nil, got %v", got)
+	}
+}
@@ -0,0 +1,112 @@
+package ansifonts
+
+import "fmt"
+
+// DefaultPatternSize is the pattern cell size used when PatternSize is zero
+const DefaultPatternSize = 1
+
+// bayerMatrix is the 4x4 ordered dither threshold matrix
+var bayerMatrix = [4][4]int{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// patternSizePixels returns the effective pattern cell size in pixels
+func patternSizePixels(options RenderOptions) int {
+	if options.PatternSize <= 0 {
+		return DefaultPatternSize
+	}
+	return options.PatternSize
+}
+
+// patternSecondaryColor returns the second color of a pattern. It defaults to
+// the gradient end color for dithering and to the primary color darkened by
+// half for every other pattern.
+func patternSecondaryColor(options RenderOptions, primaryHex string) string {
+	if options.PatternColor != "" {
+		return options.PatternColor
+	}
+	if options.Pattern == DitherPattern && options.UseGradient && options.GradientColor != "" {
+		return options.GradientColor
+	}
+	return blendHex(primaryHex, "#000000", 0.5)
+}
+
+// usesSecondaryColor reports whether glyph pixel (px, py) takes the pattern's
+// second color. Pixels are counted from the top-left of the text block, with
+// two pixel rows per character cell. ramp runs from 0 to 1 across the block
+// and only drives the dither.
+func usesSecondaryColor(pattern FillPattern, size, px, py int, ramp float64) bool {
+	switch pattern {
+	case StripePattern:
+		return (py/size)%2 == 1
+	case CheckerPattern:
+		return (px/size+py/size)%2 == 1
+	case HatchPattern:
+		return ((px+py)/size)%3 == 2
+	case DitherPattern:
+		threshold := (float64(bayerMatrix[(py/size)%4][(px/size)%4]) + 0.5) / 16
+		return threshold < ramp
+	case ScanlinePattern:
+		return py%(size+1) == size
+	}
+	return false
+}
+
+// patternRamp returns how far pixel (px, py) lies along the gradient direction
+// of a block with the given size in cells, from 0 to 1
+func patternRamp(direction GradientDirection, px, py, blockWidth, blockHeight int) float64 {
+	var ramp float64
+	switch direction {
+	case UpDown, DownUp:
+		if blockHeight*2 > 1 {
+			ramp = float64(py) / float64(blockHeight*2-1)
+		}
+	case LeftRight, RightLeft:
+		if blockWidth > 1 {
+			ramp = float64(px) / float64(blockWidth-1)
+		}
+	}
+	if direction == DownUp || direction == RightLeft {
+		ramp = 1.0 - ramp
+	}
+	return ramp
+}
+
+// patternCell styles a main text cell with the fill pattern. Each cell holds
+// two pixel rows; when a full block's pixels differ in color it is drawn as an
+// upper half block in the top color over a background in the bottom color.
+func patternCell(cell Cell, primaryHex string, options RenderOptions, blockWidth, blockHeight int) string {
+	size := patternSizePixels(options)
+	secondaryHex := patternSecondaryColor(options, primaryHex)
+	if options.Pattern == DitherPattern {
+		// The dither replaces the smooth gradient with its two colors
+		primaryHex = options.TextColor
+	}
+
+	pixelColor := func(half int) string {
+		px, py := cell.Column, cell.Row*2+half
+		ramp := patternRamp(options.GradientDirection, px, py, blockWidth, blockHeight)
+		if usesSecondaryColor(options.Pattern, size, px, py, ramp) {
+			return secondaryHex
+		}
+		return primaryHex
+	}
+
+	char := cell.Char
+	topHex, bottomHex := pixelColor(0), pixelColor(1)
+	switch char {
+	case '▄':
+		topHex = bottomHex
+	case '█':
+		if topHex != bottomHex && options.Fill.FullBlock == 0 {
+			tr, tg, tb := hexToRGB(topHex)
+			br, bg, bb := hexToRGB(bottomHex)
+			return fmt.Sprintf("\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀\x1b[0m", tr, tg, tb, br, bg, bb)
+		}
+	}
+	r, g, b := hexToRGB(topHex)
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", r, g, b, string(options.Fill.remap(char)))
+}
@@ -0,0 +1,74 @@
+package ansifonts
+
+import (
+	"reflect"
+	"testing"
+)
+
+// patternGrid draws which pixels of a 4x4 area take the second color ('#')
+func patternGrid(pattern FillPattern, size int, ramp float64) []string {
+	rows := make([]string, 4)
+	for py := range rows {
+		for px := range 4 {
+			if usesSecondaryColor(pattern, size, px, py, ramp) {
+				rows[py] += "#"
+			} else {
+				rows[py] += "."
+			}
+		}
+	}
+	return rows
+}
+
+func TestUsesSecondaryColor(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  FillPattern
+		size     int
+		ramp     float64
+		expected []string
+	}{
+		{"none", NoPattern, 1, 0.5, []string{"....", "....", "....", "...."}},
+		{"stripes", StripePattern, 1, 0, []string{"....", "####", "....", "####"}},
+		{"wide stripes", StripePattern, 2, 0, []string{"....", "....", "####", "####"}},
+		{"checker", CheckerPattern, 1, 0, []string{".#.#", "#.#.", ".#.#", "#.#."}},
+		{"wide checker", CheckerPattern, 2, 0, []string{"..##", "..##", "##..", "##.."}},
+		{"hatch", HatchPattern, 1, 0, []string{"..#.", ".#..", "#..#", "..#."}},
+		{"dither start", DitherPattern, 1, 0, []string{"....", "....", "....", "...."}},
+		{"dither middle", DitherPattern, 1, 0.5, []string{"#.#.", ".#.#", "#.#.", ".#.#"}},
+		{"dither end", DitherPattern, 1, 1, []string{"####", "####", "####", "####"}},
+		{"scanlines", ScanlinePattern, 1, 0, []string{"....", "####", "....", "####"}},
+		{"wide scanlines", ScanlinePattern, 2, 0, []string{"....", "....", "####", "...."}},
+	}
+	for _, tt := range tests {
+		if got := patternGrid(tt.pattern, tt.size, tt.ramp); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", tt.name, joinLines(tt.expected), joinLines(got))
+		}
+	}
+}
+
+func TestPatternCell(t *testing.T) {
+	options := DefaultRenderOptions()
+	options.Pattern = StripePattern
+	options.PatternColor = "#0000FF"
+	tests := []struct {
+		name     string
+		cell     Cell
+		fill     rune
+		expected string
+	}{
+		// A full block whose pixel rows differ splits into two colors
+		{"split block", Cell{Char: '█', Row: 0}, 0, "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀\x1b[0m"},
+		{"lower half", Cell{Char: '▄', Row: 0}, 0, "\x1b[38;2;0;0;255m▄\x1b[0m"},
+		{"upper half", Cell{Char: '▀', Row: 0}, 0, "\x1b[38;2;255;0;0m▀\x1b[0m"},
+		// A custom fill character cannot show two colors and takes the top one
+		{"fill character", Cell{Char: '█', Row: 0}, '#', "\x1b[38;2;255;0;0m#\x1b[0m"},
+	}
+	for _, tt := range tests {
+		cellOptions := options
+		cellOptions.Fill.FullBlock = tt.fill
+		if got := patternCell(tt.cell, "#FF0000", cellOptions, 4, 2); got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -41,6 +41,28 @@ func DetectHalfPixelUsage(text string, fontData FontData, scaleFactor float64) b
 	return false
 }
 
+// DetectHalfPixelUsageWithOptions extends DetectHalfPixelUsage with the glyph
+// transforms in options. A synthetic oblique shifts pixel rows independently,
+// which produces half-pixels even in fonts made only of full blocks. A quarter
+// turn can do the same, so rotated text is checked on the composed block.
+func DetectHalfPixelUsageWithOptions(text string, fontData FontData, options RenderOptions) bool {
+	if options.ObliqueShear > 0 && strings.TrimSpace(text) != "" {
+		return true
+	}
+	if DetectHalfPixelUsage(text, fontData, options.ScaleFactor) {
+		return true
+	}
+	if options.Rotation == Rotate90 || options.Rotation == Rotate270 {
+		plainBlock, _ := renderPlainBlock(strings.Split(text, "\n"), fontData, options, nil)
+		for _, line := range plainBlock {
+			if strings.ContainsAny(line, "▀▄") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // RenderTextWithFont renders text using the specified font with advanced rendering options
 func RenderTextWithFont(text string, fontData FontData, options RenderOptions) []string {
 	if text == "" {
@@ -56,44 +78,27 @@ func RenderTextWithFont(text string, fontData FontData, options RenderOptions) [
 		return []string{}
 	}
 
-	// Encapsulate shadow compatibility logic within the library
-	// If half-pixels are detected and shadows are enabled with non-zero offsets,
-	// automatically disable shadows to prevent visual artifacts
-	if options.ShadowEnabled && (options.ShadowHorizontalOffset != 0 || options.ShadowVerticalOffset != 0) {
-		hasHalfPixels := DetectHalfPixelUsage(text, fontData, options.ScaleFactor)
-		if hasHalfPixels {
-			options.ShadowEnabled = false
-		}
-	}
+	options = disableHalfPixelShadows(text, fontData, options)
 
 	// Split text into lines to process each one independently
 	textLines := strings.Split(text, "\n")
+
+	// Vertical layout, rotation, mirroring and reflections work on the whole block at once
+	if composesWholeBlock(options) {
+		return renderTransformedText(textLines, fontData, options)
+	}
 	var allRenderedLines []string
-	var renderedTextLines [][]string
+	var slots [][]int
+	if options.PaletteMode != NoPalette {
+		slots = paletteSlots(textLines, options.PaletteMode)
+	}
 
 	// First pass: render each text line and find the maximum width for alignment
-	maxTextLineWidth := 0
-	for lineIndex, line := range textLines {
-		if line == "" {
-			renderedTextLines = append(renderedTextLines, []string{""})
-			continue
-		}
-
-		lineRendered := renderTextWithFont(line, fontData, options.CharSpacing, float64(options.WordSpacing), options.ScaleFactor, options.CustomKerning[lineIndex])
-		lineRendered = stripEmptyLines(lineRendered)
-
-		lineWidth := 0
-		for _, row := range lineRendered {
-			lineWidth = max(lineWidth, utf8.RuneCountInString(stripANSI(row)))
-		}
-
-		maxTextLineWidth = max(maxTextLineWidth, lineWidth)
-		renderedTextLines = append(renderedTextLines, lineRendered)
-	}
+	layouts, maxTextLineWidth := layoutTextLines(textLines, fontData, options)
 
 	// Second pass: apply alignment, styling, and shadow to each text line's block
-	for i, lineRendered := range renderedTextLines {
-		if len(lineRendered) == 1 && lineRendered[0] == "" {
+	for i, layout := range layouts {
+		if layout.rows == nil {
 			if i > 0 {
 				allRenderedLines = append(allRenderedLines, "")
 			}
@@ -101,10 +106,16 @@ func RenderTextWithFont(text string, fontData FontData, options RenderOptions) [
 		}
 
 		// Apply alignment to the current line's rendered block
-		alignedBlock := applyAlignmentToTextLine(lineRendered, maxTextLineWidth, options.Alignment)
+		lineOptions := layout.options
+		var lineSources sourceMap
+		if slots != nil {
+			lineSources = layout.sources.toSlots(slots[i])
+		}
+		alignedBlock := applyAlignmentToTextLine(layout.rows, maxTextLineWidth, lineOptions.Alignment)
+		alignedSources := alignSources(lineSources, layout.rows, maxTextLineWidth, lineOptions.Alignment)
 
 		// Apply styling and shadow
-		finalBlock := applyStylingAndShadow(alignedBlock, options)
+		finalBlock := applyStylingAndShadow(alignedBlock, alignedSources, nil, lineOptions)
 
 		// Add configurable spacing between text lines
 		if i > 0 && len(allRenderedLines) > 0 {
@@ -116,184 +127,211 @@ func RenderTextWithFont(text string, fontData FontData, options RenderOptions) [
 		allRenderedLines = append(allRenderedLines, finalBlock...)
 	}
 
-	// Final pass to ensure all lines have the same width for consistent rendering
-	maxWidth := 0
-	for _, line := range allRenderedLines {
-		maxWidth = max(maxWidth, utf8.RuneCountInString(stripANSI(line)))
-	}
+	// Final pass to ensure all lines have the same width for consistent rendering,
+	// then add the background, padding and frame around the finished block
+	return decorateBlock(padToCommonWidth(allRenderedLines), options)
+}
 
-	for i, line := range allRenderedLines {
-		lineWidth := utf8.RuneCountInString(stripANSI(line))
-		if lineWidth < maxWidth {
-			allRenderedLines[i] = line + strings.Repeat(" ", maxWidth-lineWidth)
+// disableHalfPixelShadows encapsulates shadow compatibility logic within the
+// library. If half-pixels are detected and shadows or extrusions are enabled
+// with non-zero offsets, it disables them to prevent visual artifacts.
+func disableHalfPixelShadows(text string, fontData FontData, options RenderOptions) RenderOptions {
+	if (options.ShadowEnabled || options.ExtrusionEnabled) && (options.ShadowHorizontalOffset != 0 || options.ShadowVerticalOffset != 0) {
+		hasHalfPixels := DetectHalfPixelUsageWithOptions(text, fontData, options)
+		if hasHalfPixels {
+			options.ShadowEnabled = false
+			options.ExtrusionEnabled = false
 		}
 	}
+	return options
+}
 
-	return allRenderedLines
+// textLineLayout is a text line rendered by the first pass of RenderTextWithFont
+type textLineLayout struct {
+	rows      []string      // Plain rows without empty rows at the top and bottom, nil for an empty line
+	sources   sourceMap     // Rune index plus one behind every cell of rows
+	positions []float64     // Start column of every rune
+	top       int           // Rows cut from the top of the rendered line
+	options   RenderOptions // Options of the line, with its line style applied
+	fontData  FontData      // Font of the line
 }
 
-// applyStylingAndShadow provides a unified way to render a text block and its shadow.
-// It correctly handles both single colors and independent gradients.
-func applyStylingAndShadow(plainBlock []string, options RenderOptions) []string {
-	if len(plainBlock) == 0 {
-		return plainBlock
-	}
+// layoutTextLines renders each text line without styling and returns the
+// lines together with the width of the widest one
+func layoutTextLines(textLines []string, fontData FontData, options RenderOptions) ([]textLineLayout, int) {
+	layouts := make([]textLineLayout, len(textLines))
+	maxTextLineWidth := 0
+	for lineIndex, line := range textLines {
+		lineOptions, lineFont := options.lineOptions(lineIndex, fontData)
+		if _, styled := options.LineStyles[lineIndex]; styled && lineHasHalfPixels(line, lineFont, lineOptions) {
+			lineOptions.ShadowEnabled = false
+			lineOptions.ExtrusionEnabled = false
+		}
+		layouts[lineIndex] = textLineLayout{options: lineOptions, fontData: lineFont}
+		if line == "" {
+			continue
+		}
 
-	// --- Parameter Setup ---
-	var shadowPixels, verticalShadowPixels int
-	var shadowChar rune
-	if options.ShadowEnabled {
-		shadowPixels = options.ShadowHorizontalOffset
-		verticalShadowPixels = options.ShadowVerticalOffset
-		shadowChar = shadowStyleOptions[options.ShadowStyle].Char
-	}
+		lineRendered, lineSources, positions := renderTextLayout(line, lineFont, lineOptions, options.CustomKerning[lineIndex])
+		start, end := emptyLineBounds(lineRendered)
+		layouts[lineIndex].rows = lineRendered[start:end]
+		layouts[lineIndex].sources = lineSources.cut(start, end)
+		layouts[lineIndex].positions = positions
+		layouts[lineIndex].top = start
 
-	// Gradient color setup
-	isGradient := options.UseGradient && options.GradientColor != options.TextColor
-	startColorHex := options.TextColor
-	var endColorHex string
-	if isGradient {
-		endColorHex = options.GradientColor
+		for _, row := range layouts[lineIndex].rows {
+			maxTextLineWidth = max(maxTextLineWidth, utf8.RuneCountInString(stripANSI(row)))
+		}
 	}
-	startR, startG, startB := hexToRGB(startColorHex)
-	endR, endG, endB := hexToRGB(endColorHex)
+	return layouts, maxTextLineWidth
+}
 
-	// Single color setup
-	shadowStyleHex := shadowStyleOptions[options.ShadowStyle].Hex
-	var shadowColorForStyle string
-	if shadowStyleHex != "" {
-		shadowColorForStyle = shadowStyleHex
-	} else {
-		shadowColorForStyle = startColorHex // Shadow inherits main text color by default
-	}
-
-	// --- Canvas Calculation ---
-	blockHeight := len(plainBlock)
-	blockWidth := 0
-	for _, line := range plainBlock {
-		blockWidth = max(blockWidth, utf8.RuneCountInString(line))
-	}
-
-	canvasMinX, canvasMaxX := 0, blockWidth
-	canvasMinY, canvasMaxY := 0, blockHeight
-
-	if shadowPixels < 0 {
-		canvasMinX = shadowPixels
-	} else if shadowPixels > 0 {
-		canvasMaxX = blockWidth + shadowPixels
-	}
-	if verticalShadowPixels < 0 {
-		canvasMinY = verticalShadowPixels
-	} else if verticalShadowPixels > 0 {
-		canvasMaxY = blockHeight + verticalShadowPixels
-	}
-	canvasWidth := canvasMaxX - canvasMinX
-	canvasHeight := canvasMaxY - canvasMinY
-
-	// --- Canvas Creation ---
-	type canvasCell struct {
-		char    rune
-		isMain  bool
-		lineIdx int // Original row index for gradient calculation
-		charIdx int // Original col index for gradient calculation
-	}
-	canvas := make([][]canvasCell, canvasHeight)
-	for i := range canvas {
-		canvas[i] = make([]canvasCell, canvasWidth)
-		// All cells are already zero-initialized with char: 0 (rune zero value)
-		// Set them to space explicitly
-		for j := range canvas[i] {
-			canvas[i][j] = canvasCell{char: ' '}
-		}
+// lineHasHalfPixels reports whether a styled line draws half pixels while its
+// shadow or extrusion is offset, in which case those effects are turned off for it
+func lineHasHalfPixels(line string, fontData FontData, options RenderOptions) bool {
+	if !options.ShadowEnabled && !options.ExtrusionEnabled {
+		return false
+	}
+	if options.ShadowHorizontalOffset == 0 && options.ShadowVerticalOffset == 0 {
+		return false
 	}
+	return DetectHalfPixelUsageWithOptions(line, fontData, options)
+}
 
-	// --- Render to Canvas (Shadow first, then Main Text) ---
-	if options.ShadowEnabled {
-		shadowOffsetX := -canvasMinX + shadowPixels
-		shadowOffsetY := -canvasMinY + verticalShadowPixels
-		for y, line := range plainBlock {
-			lineRunes := []rune(line)
-			for x, r := range lineRunes {
-				if r != ' ' {
-					targetX, targetY := shadowOffsetX+x, shadowOffsetY+y
-					if targetX >= 0 && targetX < canvasWidth && targetY >= 0 && targetY < canvasHeight {
-						canvas[targetY][targetX] = canvasCell{char: shadowChar, isMain: false, lineIdx: y, charIdx: x}
-					}
-				}
-			}
-		}
+// applyStylingAndShadow styles a plain text block by running the effects of
+// options on a canvas around it, from glow and shadow to the glyphs and their
+// gradient, then any custom effects. sources holds the palette slot behind
+// each cell and may be nil when no palette is used. With lines, sources hold
+// the text line behind each cell instead, and lines colors it.
+func applyStylingAndShadow(plainBlock []string, sources sourceMap, lines *lineColors, options RenderOptions) []string {
+	if len(plainBlock) == 0 {
+		return plainBlock
 	}
 
-	mainOffsetX := -canvasMinX
-	mainOffsetY := -canvasMinY
-	for y, line := range plainBlock {
-		lineRunes := []rune(line)
-		for x, r := range lineRunes {
-			if r != ' ' {
-				targetX, targetY := mainOffsetX+x, mainOffsetY+y
-				if targetX >= 0 && targetX < canvasWidth && targetY >= 0 && targetY < canvasHeight {
-					canvas[targetY][targetX] = canvasCell{char: r, isMain: true, lineIdx: y, charIdx: x}
-				}
-			}
+	effects := styleEffects(options)
+	blockWidth, blockHeight := maxRowLen(plainBlock), len(plainBlock)
+	canvasMinX, canvasMaxX, canvasMinY, canvasMaxY := effectBounds(blockWidth, blockHeight, effects)
+
+	canvas := &Canvas{
+		Cells:   make([][]Cell, canvasMaxY-canvasMinY),
+		Text:    plainBlock,
+		TextX:   -canvasMinX,
+		TextY:   -canvasMinY,
+		sources: sources,
+		lines:   lines,
+		options: options,
+	}
+	for y := range canvas.Cells {
+		canvas.Cells[y] = make([]Cell, canvasMaxX-canvasMinX)
+		for x := range canvas.Cells[y] {
+			canvas.Cells[y][x] = Cell{Char: ' '}
 		}
 	}
+	for _, effect := range effects {
+		effect.Apply(canvas)
+	}
+	return writeCanvas(canvas, options)
+}
 
-	// --- Convert Canvas to Styled Strings ---
+// writeCanvas converts the canvas cells into styled strings
+func writeCanvas(canvas *Canvas, options RenderOptions) []string {
+	blockWidth, blockHeight := maxRowLen(canvas.Text), len(canvas.Text)
 	var result []string
-	for y := range canvasHeight {
+	for _, row := range canvas.Cells {
 		var builder strings.Builder
-		for x := range canvasWidth {
-			cell := canvas[y][x]
-			if cell.char == ' ' {
-				builder.WriteRune(' ')
-				continue
-			}
-
-			var cellColorHex string
-			if isGradient {
-				var factor float64
-				switch options.GradientDirection {
-				case UpDown: // Up-Down
-					if blockHeight > 1 {
-						factor = float64(cell.lineIdx) / float64(blockHeight-1)
-					}
-				case DownUp: // Down-Up
-					if blockHeight > 1 {
-						factor = 1.0 - (float64(cell.lineIdx) / float64(blockHeight-1))
-					}
-				case LeftRight, RightLeft: // Left-Right, Right-Left
-					// For horizontal gradients, calculate factor based on the entire block width
-					// rather than individual line widths to ensure consistency across characters
-					// with varying heights (ascenders/descenders)
-					if canvasWidth > 1 {
-						// Calculate the actual x position in the canvas for gradient calculation
-						actualX := x
-						factor = float64(actualX) / float64(canvasWidth-1)
-					}
-					if options.GradientDirection == RightLeft {
-						factor = 1.0 - factor
-					}
-				}
-				r := int(float64(startR) + factor*float64(endR-startR))
-				g := int(float64(startG) + factor*float64(endG-startG))
-				b := int(float64(startB) + factor*float64(endB-startB))
-				cellColorHex = rgbToHex(clamp(r, 0, 255), clamp(g, 0, 255), clamp(b, 0, 255))
-			} else {
-				if cell.isMain {
-					cellColorHex = startColorHex
-				} else {
-					cellColorHex = shadowColorForStyle
-				}
-			}
-			// Use true color (24-bit RGB) for smoother gradients
-			r, g, b := hexToRGB(cellColorHex)
-			builder.WriteString(fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", r, g, b, string(cell.char)))
+		for _, cell := range row {
+			builder.WriteString(writeCell(cell, options, blockWidth, blockHeight))
 		}
 		result = append(result, strings.TrimRight(builder.String(), " "))
 	}
 	return result
 }
 
+// writeCell styles one canvas cell with the text color, pattern and fill
+// characters of options. blockWidth and blockHeight are the size of the text
+// block the pattern spans.
+func writeCell(cell Cell, options RenderOptions, blockWidth, blockHeight int) string {
+	if cell.Char == ' ' {
+		return " "
+	}
+
+	cellColorHex := cell.Color
+	if cellColorHex == "" {
+		cellColorHex = options.TextColor
+	}
+	if cell.FadeTo != "" {
+		cellColorHex = blendHex(cellColorHex, cell.FadeTo, cell.Depth)
+	}
+	if cell.Layer == TextLayer && options.Pattern != NoPattern {
+		return patternCell(cell, cellColorHex, options, blockWidth, blockHeight)
+	}
+	// Use true color (24-bit RGB) for smoother gradients
+	r, g, b := hexToRGB(cellColorHex)
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", r, g, b, string(options.Fill.remap(cell.Char)))
+}
+
+// canvasBounds returns the extent of the styling canvas relative to a plain
+// block of the given size, so that the shadow, extrusion, reflection, glow and
+// custom effects around the block fit. The plain block's top-left cell lands
+// at (-minX, -minY).
+func canvasBounds(blockWidth, blockHeight int, options RenderOptions) (minX, maxX, minY, maxY int) {
+	return effectBounds(blockWidth, blockHeight, styleEffects(options))
+}
+
+// longShadowLayers returns how many extrusion layers it takes for a layer
+// moving by (stepX, stepY) per layer to leave the canvas entirely
+func longShadowLayers(stepX, stepY float64, canvasWidth, canvasHeight int) int {
+	layers := canvasWidth + canvasHeight
+	if stepX != 0 {
+		layers = min(layers, int(math.Ceil(float64(canvasWidth)/math.Abs(stepX))))
+	}
+	if stepY != 0 {
+		layers = min(layers, int(math.Ceil(float64(canvasHeight)/math.Abs(stepY))))
+	}
+	return max(1, layers)
+}
+
+// reflectBlock returns the vertically mirrored block, cut to heightPercent of
+// its height. The rows nearest the text come first. Zero means the full height.
+func reflectBlock(plainBlock []string, heightPercent int) []string {
+	return mirrorCellsVertically(plainBlock)[:reflectionRowCount(len(plainBlock), heightPercent)]
+}
+
+// reflectionRowCount returns how many rows the reflection of a block with the
+// given height takes
+func reflectionRowCount(blockHeight, heightPercent int) int {
+	if heightPercent <= 0 || heightPercent > 100 {
+		heightPercent = 100
+	}
+	return max(1, (blockHeight*heightPercent+99)/100)
+}
+
+// reflectionChar picks the character for a reflected cell. Shade fades step
+// from dark to light shades as the row moves away from the text.
+func reflectionChar(r rune, row, totalRows int, fade ReflectionFade) rune {
+	if fade == FadeColor {
+		return r
+	}
+	switch row * 3 / totalRows {
+	case 0:
+		return '▓'
+	case 1:
+		return '▒'
+	default:
+		return '░'
+	}
+}
+
+// blendHex mixes two hex colors, where factor 0 returns from and 1 returns to
+func blendHex(from, to string, factor float64) string {
+	fromR, fromG, fromB := hexToRGB(from)
+	toR, toG, toB := hexToRGB(to)
+	r := int(float64(fromR) + factor*float64(toR-fromR))
+	g := int(float64(fromG) + factor*float64(toG-fromG))
+	b := int(float64(fromB) + factor*float64(toB-fromB))
+	return rgbToHex(clamp(r, 0, 255), clamp(g, 0, 255), clamp(b, 0, 255))
+}
+
 // applyAlignmentToTextLine applies alignment to a single rendered text line
 func applyAlignmentToTextLine(lineRendered []string, maxTextLineWidth int, alignment TextAlignment) []string {
 	if len(lineRendered) == 0 {
@@ -312,18 +350,7 @@ func applyAlignmentToTextLine(lineRendered []string, maxTextLineWidth int, align
 	}
 
 	// Calculate padding once for the entire text line based on its maximum width
-	var leftPadding int
-	switch alignment {
-	case LeftAlign: // Left alignment
-		leftPadding = 0
-	case CenterAlign: // Center alignment
-		leftPadding = (maxTextLineWidth - lineWidth) / 2
-	case RightAlign: // Right alignment
-		leftPadding = maxTextLineWidth - lineWidth
-	default:
-		// Default to left alignment
-		leftPadding = 0
-	}
+	leftPadding := alignmentPadding(lineWidth, maxTextLineWidth, alignment)
 
 	// Apply the same padding to all rows in this text line
 	alignedRows := make([]string, len(lineRendered))
@@ -346,6 +373,29 @@ func applyAlignmentToTextLine(lineRendered []string, maxTextLineWidth int, align
 	return alignedRows
 }
 
+// alignmentPadding returns the left padding that aligns a text line of the
+// given width within maxTextLineWidth
+func alignmentPadding(lineWidth, maxTextLineWidth int, alignment TextAlignment) int {
+	if lineWidth >= maxTextLineWidth {
+		return 0
+	}
+	switch alignment {
+	case CenterAlign: // Center alignment
+		return (maxTextLineWidth - lineWidth) / 2
+	case RightAlign: // Right alignment
+		return maxTextLineWidth - lineWidth
+	default:
+		// Left alignment and unknown values
+		return 0
+	}
+}
+
+// alignSources shifts a text line's source map by the same padding that
+// applyAlignmentToTextLine adds to its rows
+func alignSources(sources sourceMap, lineRendered []string, maxTextLineWidth int, alignment TextAlignment) sourceMap {
+	return sources.shiftRight(alignmentPadding(maxRowLen(lineRendered), maxTextLineWidth, alignment))
+}
+
 // ANSI escape sequence regex for accurate stripping
 var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
 
@@ -360,7 +410,13 @@ func stripEmptyLines(lines []string) []string {
 	if len(lines) == 0 {
 		return lines
 	}
+	start, end := emptyLineBounds(lines)
+	return lines[start:end]
+}
 
+// emptyLineBounds returns the range of lines left once empty lines are removed
+// from the top and bottom, so a source map can be cut the same way
+func emptyLineBounds(lines []string) (int, int) {
 	// Find first non-empty line from top
 	start := 0
 	for start < len(lines) {
@@ -372,9 +428,9 @@ func stripEmptyLines(lines []string) []string {
 		start++
 	}
 
-	// If no non-empty lines found, return empty slice
+	// If no non-empty lines found, return an empty range
 	if start >= len(lines) {
-		return []string{}
+		return 0, 0
 	}
 
 	// Find last non-empty line from bottom
@@ -388,16 +444,34 @@ func stripEmptyLines(lines []string) []string {
 		end--
 	}
 
-	// Return the slice without empty lines at top or bottom
-	return lines[start : end+1]
+	// Return the range without empty lines at top or bottom
+	return start, end + 1
 }
 
 // renderTextWithFont renders text using the specified font with proven rendering logic
-func renderTextWithFont(text string, fontData FontData, baseCharSpacing int, wordSpacing float64, scaleFactor float64, lineKerning map[int]int) []string {
+func renderTextWithFont(text string, fontData FontData, options RenderOptions, lineKerning map[int]int) []string {
+	lines, _ := renderTextWithSources(text, fontData, options, lineKerning)
+	return lines
+}
+
+// renderTextWithSources renders text like renderTextWithFont and also returns
+// the index of the source character behind every cell, plus one
+func renderTextWithSources(text string, fontData FontData, options RenderOptions, lineKerning map[int]int) ([]string, sourceMap) {
+	lines, sources, _ := renderTextLayout(text, fontData, options, lineKerning)
+	return lines, sources
+}
+
+// renderTextLayout renders text like renderTextWithSources and also returns
+// the start column of every rune, before rounding to whole cells
+func renderTextLayout(text string, fontData FontData, options RenderOptions, lineKerning map[int]int) ([]string, sourceMap, []float64) {
 	if text == "" {
-		return []string{}
+		return []string{}, nil, nil
 	}
 
+	baseCharSpacing := options.CharSpacing
+	wordSpacing := float64(options.WordSpacing)
+	scaleFactor := options.ScaleFactor
+
 	// Analyze descender properties for this font and scale
 	descenderInfo := analyzeDescenderProperties(fontData, scaleFactor)
 
@@ -415,7 +489,7 @@ func renderTextWithFont(text string, fontData FontData, baseCharSpacing int, wor
 	}
 
 	if maxCharHeight == 0 {
-		return []string{"Font has no character data"}
+		return []string{"Font has no character data"}, nil, nil
 	}
 
 	// Determine a default width for missing characters or the 'space' character
@@ -474,22 +548,27 @@ func renderTextWithFont(text string, fontData FontData, baseCharSpacing int, wor
 					if info, hasDescenderInfo := descenderInfo[charStr]; hasDescenderInfo {
 						// Adjust character bitmap for proper descender alignment
 						adjustedBitmap := adjustCharacterForDescenders(filteredBitmapLines, info, maxCharHeight)
+						// Apply synthetic bold/oblique so widths and kerning use the transformed glyph
+						adjustedBitmap = applySyntheticStyle(adjustedBitmap, options.BoldStrength, options.ObliqueShear, 0)
 						adjustedBitmaps[charStr] = adjustedBitmap
 						charWidths[charStr] = maxRowLen(adjustedBitmap)
 						charHeights[charStr] = len(adjustedBitmap)
 						charOffsets[charStr] = 0 // Offset is already applied in adjustedBitmap
 					} else {
 						// Fallback to original logic for characters without descender info
-						charWidths[charStr] = maxRowLen(filteredBitmapLines)
-						charHeights[charStr] = len(filteredBitmapLines)
-						adjustedBitmaps[charStr] = filteredBitmapLines
-
 						// Calculate vertical offset to center characters that are shorter than max height
 						if len(filteredBitmapLines) < maxCharHeight {
 							charOffsets[charStr] = (maxCharHeight - len(filteredBitmapLines)) / 2
 						} else {
 							charOffsets[charStr] = 0
 						}
+
+						// Shear from the bottom of the line, where the centered glyph does not reach
+						rowsBelow := max(0, maxCharHeight-charOffsets[charStr]-len(filteredBitmapLines))
+						filteredBitmapLines = applySyntheticStyle(filteredBitmapLines, options.BoldStrength, options.ObliqueShear, rowsBelow)
+						charWidths[charStr] = maxRowLen(filteredBitmapLines)
+						charHeights[charStr] = len(filteredBitmapLines)
+						adjustedBitmaps[charStr] = filteredBitmapLines
 					}
 				}
 			} else {
@@ -523,10 +602,13 @@ func renderTextWithFont(text string, fontData FontData, baseCharSpacing int, wor
 	}
 
 	var result []string
+	var sources sourceMap
+	var positions []float64
 
 	// Render the text row by row
 	for i := range maxCharHeight {
 		lineRunes := make([]rune, 0)
+		lineSources := make([]int, 0)
 		charStartPositions := make([]float64, len(runes)) // Use float64 for half-pixel precision
 
 		if len(runes) > 0 {
@@ -575,6 +657,10 @@ func renderTextWithFont(text string, fontData FontData, baseCharSpacing int, wor
 				charStartPositions[idx] = charStartPositions[idx-1] + prevCharTotalAdvance
 			}
 		}
+		if i == 0 {
+			// The top row has no half-pixel adjustments, so it holds the nominal positions
+			positions = charStartPositions
+		}
 
 		// Second pass: Place each character's fragment onto the lineRunes canvas
 		cumulativeError := 0.0 // Track cumulative rounding errors
@@ -614,6 +700,7 @@ func renderTextWithFont(text string, fontData FontData, baseCharSpacing int, wor
 			requiredLength := renderXOffset + utf8.RuneCountInString(fragment)
 			for len(lineRunes) < requiredLength {
 				lineRunes = append(lineRunes, ' ')
+				lineSources = append(lineSources, 0)
 			}
 
 			// Place the fragment into lineRunes at the calculated position
@@ -621,20 +708,28 @@ func renderTextWithFont(text string, fontData FontData, baseCharSpacing int, wor
 			for fragIdx, fragRune := range fragmentRunes {
 				targetPos := renderXOffset + fragIdx
 				if targetPos >= 0 && targetPos < len(lineRunes) {
+					// Fill the glyph with its own source character when requested
+					if options.Fill.GlyphLetters && fragRune != ' ' {
+						fragRune = runes[idx]
+					}
 					// Place character, preserving original proven logic
 					if fragRune != ' ' || lineRunes[targetPos] == ' ' {
 						lineRunes[targetPos] = fragRune
+						if fragRune != ' ' {
+							lineSources[targetPos] = idx + 1
+						}
 					}
 				}
 			}
 		}
 
 		// Output the line, trimming any trailing spaces
-		resultLine := string(lineRunes)
-		result = append(result, strings.TrimRight(resultLine, " "))
+		resultLine := strings.TrimRight(string(lineRunes), " ")
+		result = append(result, resultLine)
+		sources = append(sources, lineSources[:utf8.RuneCountInString(resultLine)])
 	}
 
-	return result
+	return result, sources, positions
 }
 
 // isSpaceAtWordBoundary determines if a space character is at a word boundary
@@ -714,6 +809,14 @@ func rgbToHex(r, g, b int) string {
 	return fmt.Sprintf("#%02X%02X%02X", r, g, b)
 }
 
+// abs returns the absolute value of an integer
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
 // clamp ensures a value is within a specified range
 func clamp(value, minVal, maxVal int) int {
 	return max(minVal, min(value, maxVal))
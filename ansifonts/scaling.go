@@ -48,6 +48,22 @@ func ansiToExpandedBinary(ansiLines []string) [][]int {
 	return binary
 }
 
+// lettersToBlocks redraws cells filled with glyph letters as full blocks, so
+// ansiToExpandedBinary sees their pixels. Block characters and spaces stay.
+func lettersToBlocks(ansiLines []string) []string {
+	result := make([]string, len(ansiLines))
+	for i, line := range ansiLines {
+		result[i] = strings.Map(func(r rune) rune {
+			switch r {
+			case '█', '▀', '▄', ' ':
+				return r
+			}
+			return '█'
+		}, line)
+	}
+	return result
+}
+
 // Helper function to convert expanded binary back to ANSI format
 func expandedBinaryToAnsi(binary [][]int) []string {
 	if len(binary) == 0 {
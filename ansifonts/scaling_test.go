@@ -0,0 +1,53 @@
+package ansifonts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScaleCharacter_ExistingFontAtScale2(t *testing.T) {
+	font, err := LoadFont("ithaca")
+	if err != nil {
+		t.Fatalf("failed to load font: %v", err)
+	}
+
+	got := scaleCharacter(font.FontData.Characters["a"], 2)
+	blank := "                    "
+	expected := []string{
+		blank, blank, blank, blank, blank, blank, blank, blank, blank, blank,
+		"    ████████████    ",
+		"    ████████████    ",
+		"████████    ████████",
+		"████████    ████████",
+		"            ████████",
+		"            ████████",
+		"    ████████████████",
+		"    ████████████████",
+		"████████    ████████",
+		"████████    ████████",
+		"████████    ████████",
+		"████████    ████████",
+		"    ████████████████",
+		"    ████████████████",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected:\n%q\ngot:\n%q", expected, got)
+	}
+}
+
+func TestScaleCharacter_OtherCharactersStayOff(t *testing.T) {
+	// Only block characters are pixels; anything else in a font bitmap is blank
+	got := scaleCharacter([]string{"█#▀", "o▄ "}, 2)
+	expected := []string{"██  ██", "██    ", "      ", "  ██  "}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestLettersToBlocks(t *testing.T) {
+	got := lettersToBlocks([]string{"AB ▀", "▄ c█"})
+	expected := []string{"██ ▀", "▄ ██"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
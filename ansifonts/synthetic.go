@@ -0,0 +1,84 @@
+package ansifonts
+
+import (
+	"math"
+	"strings"
+)
+
+// applySyntheticStyle applies synthetic emboldening and oblique shear to a glyph bitmap.
+// Both transforms operate on the expanded binary representation so half-block
+// glyphs stay correct. The returned bitmap keeps the glyph's height.
+// rowsBelow is the number of rows between the bottom of the bitmap and the
+// bottom of the line, which the shear is anchored at.
+func applySyntheticStyle(bitmap []string, boldStrength int, obliqueShear float64, rowsBelow int) []string {
+	if (boldStrength <= 0 && obliqueShear <= 0) || len(bitmap) == 0 {
+		return bitmap
+	}
+
+	width := maxRowLen(bitmap)
+	if width == 0 {
+		return bitmap
+	}
+
+	// Pad rows to a common width so the expanded binary keeps every row
+	padded := make([]string, len(bitmap))
+	for i, row := range bitmap {
+		padded[i] = row + strings.Repeat(" ", width-len([]rune(row)))
+	}
+	binary := ansiToExpandedBinary(padded)
+
+	if boldStrength > 0 {
+		binary = emboldenBitmap(binary, boldStrength)
+	}
+	if obliqueShear > 0 {
+		binary = shearBitmap(binary, obliqueShear, rowsBelow*2)
+	}
+
+	return expandedBinaryToAnsi(binary)
+}
+
+// emboldenBitmap dilates every lit pixel horizontally by strength pixels to the right.
+// The bitmap grows by strength columns so the thickened right edge is never clipped.
+func emboldenBitmap(bitmap [][]int, strength int) [][]int {
+	result := make([][]int, len(bitmap))
+	for y, row := range bitmap {
+		newRow := make([]int, len(row)+strength)
+		for x, pixel := range row {
+			if pixel == 1 {
+				for dx := 0; dx <= strength; dx++ {
+					newRow[x+dx] = 1
+				}
+			}
+		}
+		result[y] = newRow
+	}
+	return result
+}
+
+// shearBitmap slants the bitmap to the right by shifting each pixel row progressively.
+// Rows move by their distance from the line's bottom, pixelsBelow rows under the
+// bitmap, so glyphs of different heights step at the same rows of the line. The
+// bottom row stays in place and the top and bottom halves of a character cell
+// can land on different columns.
+func shearBitmap(bitmap [][]int, shear float64, pixelsBelow int) [][]int {
+	height := len(bitmap)
+	if height == 0 {
+		return bitmap
+	}
+
+	// Shift relative to the bottom row so the bitmap does not grow on the left
+	shiftAt := func(y int) int {
+		return int(math.Round(float64(height-1-y+pixelsBelow)*shear)) - int(math.Round(float64(pixelsBelow)*shear))
+	}
+	maxShift := shiftAt(0)
+	result := make([][]int, height)
+	for y, row := range bitmap {
+		shift := shiftAt(y)
+		newRow := make([]int, len(row)+maxShift)
+		for x, pixel := range row {
+			newRow[x+shift] = pixel
+		}
+		result[y] = newRow
+	}
+	return result
+}
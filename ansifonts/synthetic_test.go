@@ -0,0 +1,50 @@
+package ansifonts
+
+import "testing"
+
+// columnBitmap returns a one pixel wide bitmap of the given height with every pixel on
+func columnBitmap(height int) [][]int {
+	bitmap := make([][]int, height)
+	for y := range bitmap {
+		bitmap[y] = []int{1}
+	}
+	return bitmap
+}
+
+// pixelColumn returns the column of the lit pixel in a bitmap row
+func pixelColumn(row []int) int {
+	for x, pixel := range row {
+		if pixel == 1 {
+			return x
+		}
+	}
+	return -1
+}
+
+func TestShearBitmap_SharedBaseline(t *testing.T) {
+	// A short glyph sits above the bottom of the line next to a tall one;
+	// the shift between their rows must be the same on every row they share
+	const tallHeight, shortHeight, shortBelow = 6, 3, 2
+	for _, shear := range []float64{0.25, 0.5, 0.75, 1} {
+		tall := shearBitmap(columnBitmap(tallHeight), shear, 0)
+		short := shearBitmap(columnBitmap(shortHeight), shear, shortBelow)
+
+		first := tallHeight - shortBelow - shortHeight
+		offset := pixelColumn(tall[first]) - pixelColumn(short[0])
+		for y := 1; y < shortHeight; y++ {
+			if got := pixelColumn(tall[first+y]) - pixelColumn(short[y]); got != offset {
+				t.Errorf("shear %g, row %d: expected the glyphs %d columns apart, got %d", shear, y, offset, got)
+			}
+		}
+	}
+}
+
+func TestShearBitmap_BottomRowStays(t *testing.T) {
+	sheared := shearBitmap(columnBitmap(4), 0.5, 3)
+	if got := pixelColumn(sheared[3]); got != 0 {
+		t.Errorf("expected the bottom row in column 0, got %d", got)
+	}
+	if got := len(sheared[3]); got != 2 {
+		t.Errorf("expected rows 2 columns wide, got %d", got)
+	}
+}
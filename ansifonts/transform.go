@@ -0,0 +1,442 @@
+package ansifonts
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// composesWholeBlock reports whether options require the whole text block to be
+// composed before styling, instead of styling each text line separately.
+// Reflections mirror the whole block, so they need it as well.
+func composesWholeBlock(options RenderOptions) bool {
+	return options.VerticalLayout || options.Rotation != NoRotation || options.FlipHorizontal || options.FlipVertical || options.ReflectionEnabled
+}
+
+// renderTransformedText renders text whose layout is composed as a whole.
+// The plain block is composed first, then styled once so gradients, shadows
+// and reflections follow the rotated or mirrored result. The colors of
+// LineStyles follow the cells of their line, unless a palette colors the text.
+func renderTransformedText(textLines []string, fontData FontData, options RenderOptions) []string {
+	var slots [][]int
+	colorsLines := options.PaletteMode == NoPalette && hasLineColors(options)
+	if options.PaletteMode != NoPalette {
+		slots = paletteSlots(textLines, options.PaletteMode)
+	} else if colorsLines {
+		slots = lineSlots(textLines)
+	}
+	plainBlock, sources := renderPlainBlock(textLines, fontData, options, slots)
+	if len(plainBlock) == 0 {
+		return []string{}
+	}
+	var lines *lineColors
+	if colorsLines {
+		lines = newLineColors(textLines, sources, options)
+	}
+	return decorateBlock(padToCommonWidth(applyStylingAndShadow(plainBlock, sources, lines, options)), options)
+}
+
+// hasLineColors reports whether any line style sets a text or gradient color
+func hasLineColors(options RenderOptions) bool {
+	for _, style := range options.LineStyles {
+		if style.TextColor != "" || style.GradientColor != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// lineSlots numbers every rune by its text line, so a source map built from
+// them tells which line each cell came from
+func lineSlots(textLines []string) [][]int {
+	slots := make([][]int, len(textLines))
+	for lineIndex, line := range textLines {
+		slots[lineIndex] = make([]int, utf8.RuneCountInString(line))
+		for i := range slots[lineIndex] {
+			slots[lineIndex][i] = lineIndex
+		}
+	}
+	return slots
+}
+
+// lineColors colors the cells of a composed block by the text line they came
+// from, so the colors and gradients of LineStyles survive layout transforms
+type lineColors struct {
+	options map[int]RenderOptions // Options of every text line with a color style
+	bounds  map[int]Rect          // Cells of every text line within the block, keyed by line plus one
+}
+
+// newLineColors collects the colors of the styled lines of textLines and the
+// cells their glyphs cover in a block whose sources come from lineSlots
+func newLineColors(textLines []string, sources sourceMap, options RenderOptions) *lineColors {
+	lines := &lineColors{options: make(map[int]RenderOptions), bounds: charBoxes(sources, 0, 0)}
+	for lineIndex := range textLines {
+		style, ok := options.LineStyles[lineIndex]
+		if !ok || (style.TextColor == "" && style.GradientColor == "") {
+			continue
+		}
+		lines.options[lineIndex], _ = options.lineOptions(lineIndex, FontData{})
+	}
+	return lines
+}
+
+// color returns the color of cell (x, y) of text line index, or "" for a line
+// that takes the block colors. A line gradient runs across the cells of its
+// line in the block's gradient direction.
+func (l *lineColors) color(index, x, y int) string {
+	options, ok := l.options[index]
+	if !ok {
+		return ""
+	}
+	if !options.UseGradient || options.GradientColor == options.TextColor {
+		return options.TextColor
+	}
+	bounds := l.bounds[index+1]
+	var factor float64
+	switch options.GradientDirection {
+	case UpDown, DownUp:
+		if bounds.Height > 1 {
+			factor = float64(y-bounds.Y) / float64(bounds.Height-1)
+		}
+	case LeftRight, RightLeft:
+		if bounds.Width > 1 {
+			factor = float64(x-bounds.X) / float64(bounds.Width-1)
+		}
+	}
+	if options.GradientDirection == DownUp || options.GradientDirection == RightLeft {
+		factor = 1 - factor
+	}
+	return blendHex(options.TextColor, options.GradientColor, factor)
+}
+
+// renderPlainBlock composes the unstyled text block and applies the layout
+// transforms. The source map holds the slot behind every cell, numbered by
+// slots, and is nil when slots is nil.
+func renderPlainBlock(textLines []string, fontData FontData, options RenderOptions, slots [][]int) ([]string, sourceMap) {
+	var block []string
+	var sources sourceMap
+	if options.VerticalLayout {
+		block, sources = composeVerticalBlock(textLines, fontData, options, slots)
+	} else {
+		block, sources = composeHorizontalBlock(textLines, fontData, options, slots)
+	}
+	block, sources = transformBlock(block, sources, options)
+	start, end := emptyLineBounds(block)
+	return block[start:end], sources.cut(start, end)
+}
+
+// composeHorizontalBlock lays out text lines top to bottom, as the regular renderer does
+func composeHorizontalBlock(textLines []string, fontData FontData, options RenderOptions, slots [][]int) ([]string, sourceMap) {
+	renderedTextLines := make([][]string, len(textLines))
+	renderedSources := make([]sourceMap, len(textLines))
+	maxTextLineWidth := 0
+	for lineIndex, line := range textLines {
+		if line == "" {
+			continue
+		}
+		lineOptions, lineFont := options.lineOptions(lineIndex, fontData)
+		lineRendered, lineSources := renderTextWithSources(line, lineFont, lineOptions, options.CustomKerning[lineIndex])
+		start, end := emptyLineBounds(lineRendered)
+		lineRendered = lineRendered[start:end]
+		maxTextLineWidth = max(maxTextLineWidth, maxRowLen(lineRendered))
+		renderedTextLines[lineIndex] = lineRendered
+		if slots != nil {
+			renderedSources[lineIndex] = lineSources.cut(start, end).toSlots(slots[lineIndex])
+		}
+	}
+
+	var block []string
+	var sources sourceMap
+	for i, lineRendered := range renderedTextLines {
+		if len(lineRendered) == 0 {
+			if i > 0 {
+				block = append(block, "")
+				sources = append(sources, nil)
+			}
+			continue
+		}
+		if i > 0 && len(block) > 0 {
+			for range options.LineSpacing {
+				block = append(block, "")
+				sources = append(sources, nil)
+			}
+		}
+		alignment := options.Alignment
+		if style, ok := options.LineStyles[i]; ok && style.Alignment != nil {
+			alignment = *style.Alignment
+		}
+		block = append(block, applyAlignmentToTextLine(lineRendered, maxTextLineWidth, alignment)...)
+		lineSources := alignSources(renderedSources[i], lineRendered, maxTextLineWidth, alignment)
+		if lineSources == nil {
+			lineSources = make(sourceMap, len(lineRendered))
+		}
+		sources = append(sources, lineSources...)
+	}
+	if slots == nil {
+		return block, nil
+	}
+	return block, sources
+}
+
+// composeVerticalBlock stacks the glyphs of each text line top to bottom.
+// Glyphs are separated by LineSpacing rows, adjusted by CustomKerning, and
+// aligned within their column. Each text line becomes a column, placed left
+// to right with a gap that matches LineSpacing visually.
+func composeVerticalBlock(textLines []string, fontData FontData, options RenderOptions, slots [][]int) ([]string, sourceMap) {
+	var columns [][]string
+	var columnSources []sourceMap
+	for lineIndex, line := range textLines {
+		lineOptions, lineFont := options.lineOptions(lineIndex, fontData)
+		runes := []rune(line)
+		glyphs := make([][]string, len(runes))
+		glyphSources := make([]sourceMap, len(runes))
+		columnWidth := 0
+		for i, r := range runes {
+			if r == ' ' {
+				// A space becomes a blank gap sized by the word spacing
+				glyphs[i] = make([]string, max(1, options.WordSpacing))
+				continue
+			}
+			glyphOptions := lineOptions
+			glyphOptions.Fill.GlyphLetters = false
+			glyph, sources := renderTextWithSources(string(r), lineFont, glyphOptions, nil)
+			start, end := emptyLineBounds(glyph)
+			glyph = glyph[start:end]
+			if options.Fill.GlyphLetters {
+				glyph = fillGlyphWithLetter(glyph, r)
+			}
+			glyphs[i] = glyph
+			if slots != nil {
+				glyphSources[i] = sources.cut(start, end).toSlots(slots[lineIndex][i : i+1])
+			}
+			columnWidth = max(columnWidth, maxRowLen(glyph))
+		}
+
+		var column []string
+		var sources sourceMap
+		for i, glyph := range glyphs {
+			if i > 0 {
+				gap := max(0, options.LineSpacing+options.CustomKerning[lineIndex][i])
+				for range gap {
+					column = append(column, "")
+					sources = append(sources, nil)
+				}
+			}
+			column = append(column, applyAlignmentToTextLine(glyph, columnWidth, lineOptions.Alignment)...)
+			if glyphSources[i] != nil {
+				sources = append(sources, alignSources(glyphSources[i], glyph, columnWidth, lineOptions.Alignment)...)
+			} else {
+				sources = append(sources, make(sourceMap, len(glyph))...)
+			}
+		}
+		columns = append(columns, column)
+		columnSources = append(columnSources, sources)
+	}
+
+	block := joinColumns(columns, 2*options.LineSpacing)
+	if slots == nil {
+		return block, nil
+	}
+	return block, joinSourceColumns(columns, columnSources, 2*options.LineSpacing)
+}
+
+// fillGlyphWithLetter draws every non-space cell of a glyph with its letter
+func fillGlyphWithLetter(glyph []string, letter rune) []string {
+	result := make([]string, len(glyph))
+	for i, row := range glyph {
+		runes := []rune(row)
+		for j, r := range runes {
+			if r != ' ' {
+				runes[j] = letter
+			}
+		}
+		result[i] = string(runes)
+	}
+	return result
+}
+
+// joinColumns places blocks side by side, top aligned, separated by gap columns
+func joinColumns(columns [][]string, gap int) []string {
+	height := 0
+	for _, column := range columns {
+		height = max(height, len(column))
+	}
+
+	block := make([]string, height)
+	for c, column := range columns {
+		width := maxRowLen(column)
+		for y := range height {
+			var row string
+			if y < len(column) {
+				row = column[y]
+			}
+			if c > 0 {
+				block[y] += strings.Repeat(" ", gap)
+			}
+			block[y] += row + strings.Repeat(" ", width-utf8.RuneCountInString(row))
+		}
+	}
+	return block
+}
+
+// joinSourceColumns places the source maps of columns side by side, matching
+// the layout joinColumns gives the columns themselves
+func joinSourceColumns(columns [][]string, sources []sourceMap, gap int) sourceMap {
+	height := 0
+	for _, column := range columns {
+		height = max(height, len(column))
+	}
+
+	joined := make(sourceMap, height)
+	for c, column := range columns {
+		width := maxRowLen(column)
+		for y := range height {
+			if c > 0 {
+				joined[y] = append(joined[y], make([]int, gap)...)
+			}
+			for x := range width {
+				joined[y] = append(joined[y], sources[c].at(x, y))
+			}
+		}
+	}
+	return joined
+}
+
+// transformBlock rotates and then mirrors a plain block. The transforms run on
+// the expanded binary representation, where each pixel is roughly square, so
+// half-block glyphs keep their shape. Mirroring and 180 degree rotation keep
+// each cell's character, so glyph letters survive them. Quarter turns redraw
+// glyph letters as blocks. The source map, when present, follows the cells.
+func transformBlock(block []string, sources sourceMap, options RenderOptions) ([]string, sourceMap) {
+	if len(block) == 0 {
+		return block, sources
+	}
+
+	width := maxRowLen(block)
+	padded := make([]string, len(block))
+	for i, row := range block {
+		padded[i] = row + strings.Repeat(" ", width-utf8.RuneCountInString(row))
+	}
+
+	if options.Rotation == Rotate90 || options.Rotation == Rotate270 {
+		if options.Fill.GlyphLetters {
+			padded = lettersToBlocks(padded)
+		}
+		binary := ansiToExpandedBinary(padded)
+		if options.Rotation == Rotate90 {
+			binary = rotateBitmapClockwise(binary)
+		} else {
+			binary = rotateBitmapCounterClockwise(binary)
+		}
+		padded = expandedBinaryToAnsi(binary)
+		sources = sources.rotate(options.Rotation == Rotate90, width)
+		width = maxRowLen(padded)
+	}
+
+	flipH := options.FlipHorizontal
+	flipV := options.FlipVertical
+	if options.Rotation == Rotate180 {
+		// A half turn is the same as mirroring both ways
+		flipH = !flipH
+		flipV = !flipV
+	}
+	if flipH {
+		padded = mirrorCellsHorizontally(padded)
+		sources = sources.mirrorHorizontally(width)
+	}
+	if flipV {
+		padded = mirrorCellsVertically(padded)
+		sources = sources.mirrorVertically()
+	}
+	return padded, sources
+}
+
+// rotateBitmapClockwise rotates a pixel grid a quarter turn clockwise
+func rotateBitmapClockwise(bitmap [][]int) [][]int {
+	height := len(bitmap)
+	width := 0
+	for _, row := range bitmap {
+		width = max(width, len(row))
+	}
+
+	result := make([][]int, width)
+	for y := range width {
+		result[y] = make([]int, height)
+		for x := range height {
+			src := bitmap[height-1-x]
+			if y < len(src) {
+				result[y][x] = src[y]
+			}
+		}
+	}
+	return result
+}
+
+// rotateBitmapCounterClockwise rotates a pixel grid a quarter turn counterclockwise
+func rotateBitmapCounterClockwise(bitmap [][]int) [][]int {
+	height := len(bitmap)
+	width := 0
+	for _, row := range bitmap {
+		width = max(width, len(row))
+	}
+
+	result := make([][]int, width)
+	for y := range width {
+		result[y] = make([]int, height)
+		for x := range height {
+			src := bitmap[x]
+			if width-1-y < len(src) {
+				result[y][x] = src[width-1-y]
+			}
+		}
+	}
+	return result
+}
+
+// mirrorCellsHorizontally reverses each row. Cell characters are symmetric left
+// to right, so reversing the cells mirrors the pixels exactly.
+func mirrorCellsHorizontally(block []string) []string {
+	result := make([]string, len(block))
+	for i, row := range block {
+		runes := []rune(row)
+		for l, r := 0, len(runes)-1; l < r; l, r = l+1, r-1 {
+			runes[l], runes[r] = runes[r], runes[l]
+		}
+		result[i] = string(runes)
+	}
+	return result
+}
+
+// mirrorCellsVertically reverses the row order and swaps upper and lower half
+// blocks, which mirrors the pixels inside each cell as well
+func mirrorCellsVertically(block []string) []string {
+	result := make([]string, len(block))
+	for i, row := range block {
+		runes := []rune(row)
+		for j, r := range runes {
+			switch r {
+			case '▀':
+				runes[j] = '▄'
+			case '▄':
+				runes[j] = '▀'
+			}
+		}
+		result[len(block)-1-i] = string(runes)
+	}
+	return result
+}
+
+// padToCommonWidth pads every line with spaces to the width of the widest line
+func padToCommonWidth(lines []string) []string {
+	maxWidth := 0
+	for _, line := range lines {
+		maxWidth = max(maxWidth, utf8.RuneCountInString(stripANSI(line)))
+	}
+
+	for i, line := range lines {
+		lineWidth := utf8.RuneCountInString(stripANSI(line))
+		if lineWidth < maxWidth {
+			lines[i] = line + strings.Repeat(" ", maxWidth-lineWidth)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,213 @@
+package ansifonts
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// rowColors returns the distinct true colors of every row, in order of appearance
+func rowColors(lines []string) [][]string {
+	colorPattern := regexp.MustCompile(`\x1b\[38;2;(\d+;\d+;\d+)m`)
+	rows := make([][]string, len(lines))
+	for y, line := range lines {
+		seen := map[string]bool{}
+		rows[y] = []string{}
+		for _, match := range colorPattern.FindAllStringSubmatch(line, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				rows[y] = append(rows[y], match[1])
+			}
+		}
+	}
+	return rows
+}
+
+func TestRenderTransformedText_KeepsLineColors(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	options := DefaultRenderOptions()
+	options.LineStyles = map[int]LineStyle{0: {TextColor: "#FF0000"}}
+	expected := rowColors(RenderTextWithOptions("AB\nCD", font, options))
+
+	tests := []struct {
+		name    string
+		options func(*RenderOptions)
+	}{
+		{"horizontal flip", func(o *RenderOptions) { o.FlipHorizontal = true }},
+		{"reflection", func(o *RenderOptions) { o.ReflectionEnabled, o.ReflectionHeight = true, 100 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformed := options
+			tt.options(&transformed)
+			// Rows stay in place, so the text rows keep the colors of their line
+			got := rowColors(RenderTextWithOptions("AB\nCD", font, transformed))
+			if len(got) < len(expected) || !reflect.DeepEqual(got[:len(expected)], expected) {
+				t.Errorf("expected row colors %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderTransformedText_LineGradientSpansItsLine(t *testing.T) {
+	font := loadTestFont(t, "dogica")
+	options := DefaultRenderOptions()
+	options.GradientDirection = LeftRight
+	options.LineStyles = map[int]LineStyle{0: {TextColor: "#FF0000", GradientColor: "#0000FF"}}
+	options.Rotation = Rotate90
+
+	// The gradient runs across the cells of the first line from end to end,
+	// and the second line keeps the block color
+	seen := map[string]bool{}
+	for _, row := range rowColors(RenderTextWithOptions("AB\nCD", font, options)) {
+		for _, color := range row {
+			seen[color] = true
+		}
+	}
+	for _, color := range []string{"255;0;0", "0;0;255", "255;255;255"} {
+		if !seen[color] {
+			t.Errorf("expected color %s in the rotated text, got %v", color, seen)
+		}
+	}
+}
+
+// sourceGrid returns the sources of a width x height block, with 0 for cells
+// the map leaves out
+func sourceGrid(m sourceMap, width, height int) [][]int {
+	grid := make([][]int, height)
+	for y := range grid {
+		grid[y] = make([]int, width)
+		for x := range grid[y] {
+			grid[y][x] = m.at(x, y)
+		}
+	}
+	return grid
+}
+
+func TestTransformBlock(t *testing.T) {
+	// Pixels of the block, two per cell:
+	//   ##
+	//   #.
+	//   ..
+	//   #.
+	block := []string{"█▀", "▄ "}
+	sources := sourceMap{{1, 2}, {3, 0}}
+	tests := []struct {
+		name            string
+		options         func(*RenderOptions)
+		expected        []string
+		expectedSources [][]int
+	}{
+		{"none", func(o *RenderOptions) {}, []string{"█▀", "▄ "}, [][]int{{1, 2}, {3, 0}}},
+		{"rotate 90", func(o *RenderOptions) { o.Rotation = Rotate90 }, []string{"▀ ▀█"}, [][]int{{3, 3, 1, 1}}},
+		{"rotate 180", func(o *RenderOptions) { o.Rotation = Rotate180 }, []string{" ▀", "▄█"}, [][]int{{0, 3}, {2, 1}}},
+		{"rotate 270", func(o *RenderOptions) { o.Rotation = Rotate270 }, []string{"█▄ ▄"}, [][]int{{2, 2, 3, 3}}},
+		{"flip horizontal", func(o *RenderOptions) { o.FlipHorizontal = true }, []string{"▀█", " ▄"}, [][]int{{2, 1}, {0, 3}}},
+		{"flip vertical", func(o *RenderOptions) { o.FlipVertical = true }, []string{"▀ ", "█▄"}, [][]int{{3, 0}, {1, 2}}},
+		{"rotate 90 then flip", func(o *RenderOptions) {
+			o.Rotation, o.FlipHorizontal = Rotate90, true
+		}, []string{"█▀ ▀"}, [][]int{{1, 1, 3, 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultRenderOptions()
+			tt.options(&options)
+			got, gotSources := transformBlock(block, sources, options)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected:\n%s\ngot:\n%s", joinLines(tt.expected), joinLines(got))
+			}
+			if grid := sourceGrid(gotSources, maxRowLen(got), len(got)); !reflect.DeepEqual(grid, tt.expectedSources) {
+				t.Errorf("expected sources %v, got %v", tt.expectedSources, grid)
+			}
+		})
+	}
+}
+
+func TestTransformBlock_GlyphLetters(t *testing.T) {
+	block := []string{"AA", "A "}
+	tests := []struct {
+		name     string
+		options  func(*RenderOptions)
+		expected []string
+	}{
+		// Quarter turns redraw the letters as blocks, mirroring keeps them
+		{"rotate 90", func(o *RenderOptions) { o.Rotation = Rotate90 }, []string{"▀▀██"}},
+		{"rotate 180", func(o *RenderOptions) { o.Rotation = Rotate180 }, []string{" A", "AA"}},
+		{"flip horizontal", func(o *RenderOptions) { o.FlipHorizontal = true }, []string{"AA", " A"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultRenderOptions()
+			options.Fill.GlyphLetters = true
+			tt.options(&options)
+			if got, _ := transformBlock(block, nil, options); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected:\n%s\ngot:\n%s", joinLines(tt.expected), joinLines(got))
+			}
+		})
+	}
+}
+
+// tinyFont has two small glyphs for exact layout tests
+var tinyFont = FontData{Name: "tiny", Characters: map[string][]string{
+	"A": {"█▀█", "█ █"},
+	"B": {"▄ ", "█▀"},
+}}
+
+func TestRenderPlainBlock_VerticalLayout(t *testing.T) {
+	// Each text line becomes a column, its glyphs LineSpacing rows apart and
+	// the columns twice that apart
+	textLines := []string{"AB", "B"}
+	options := DefaultRenderOptions()
+	options.VerticalLayout = true
+	block, sources := renderPlainBlock(textLines, tinyFont, options, paletteSlots(textLines, PalettePerChar))
+
+	expected := []string{
+		"█▀█  ▄ ",
+		"█ █  █▀",
+		"       ",
+		"▄      ",
+		"█▀     ",
+	}
+	expectedSources := [][]int{
+		{1, 1, 1, 0, 0, 3, 0},
+		{1, 0, 1, 0, 0, 3, 3},
+		{0, 0, 0, 0, 0, 0, 0},
+		{2, 0, 0, 0, 0, 0, 0},
+		{2, 2, 0, 0, 0, 0, 0},
+	}
+	if !reflect.DeepEqual(block, expected) {
+		t.Errorf("expected:\n%s\ngot:\n%s", joinLines(expected), joinLines(block))
+	}
+	if grid := sourceGrid(sources, 7, 5); !reflect.DeepEqual(grid, expectedSources) {
+		t.Errorf("expected sources %v, got %v", expectedSources, grid)
+	}
+}
+
+func TestRenderTransformedText_LineColorsFollowRotation(t *testing.T) {
+	textLines := []string{"A", "B"}
+	font := &Font{Name: "tiny", FontData: tinyFont}
+	cellColor := regexp.MustCompile(`^\x1b\[38;2;(\d+;\d+;\d+)m`)
+	for _, rotation := range []Rotation{Rotate90, Rotate180, Rotate270} {
+		options := DefaultRenderOptions()
+		options.Rotation = rotation
+		options.LineStyles = map[int]LineStyle{0: {TextColor: "#FF0000"}}
+
+		// Every glyph cell of the first line is red and every other one white
+		block, sources := renderPlainBlock(textLines, tinyFont, options, lineSlots(textLines))
+		lines := RenderTextWithOptions("A\nB", font, options)
+		for y, line := range lines {
+			for x, cell := range splitStyledCells(line) {
+				if y >= len(block) || x >= len([]rune(block[y])) || []rune(block[y])[x] == ' ' {
+					continue
+				}
+				expected := "255;255;255"
+				if sources.at(x, y) == 1 {
+					expected = "255;0;0"
+				}
+				if match := cellColor.FindStringSubmatch(cell); match == nil || match[1] != expected {
+					t.Errorf("rotation %d, cell (%d, %d): expected color %s, got %q", rotation, x, y, expected, cell)
+				}
+			}
+		}
+	}
+}
@@ -22,7 +22,10 @@
 //	lines := ansifonts.RenderTextWithOptions("Hello", font, options)
 package ansifonts
 
-import "fmt"
+import (
+	"fmt"
+	"unicode"
+)
 
 // FontData represents the overall structure of our .bit font file (JSON format)
 type FontData struct {
@@ -66,6 +69,74 @@ const (
 	DarkShade
 )
 
+// Rotation represents a clockwise rotation of the rendered text
+type Rotation int
+
+const (
+	NoRotation Rotation = iota
+	Rotate90
+	Rotate180
+	Rotate270
+)
+
+// ReflectionFade represents how a reflection fades away from the text
+type ReflectionFade int
+
+const (
+	FadeShade      ReflectionFade = iota // Step through ▓▒░ in the text color
+	FadeColor                            // Keep the glyph shapes and blend toward ReflectionColor
+	FadeShadeColor                       // Step through ▓▒░ while blending toward ReflectionColor
+)
+
+// FrameStyle represents the box-drawing style of the frame around the text
+type FrameStyle int
+
+const (
+	NoFrame      FrameStyle = iota
+	SingleFrame             // ┌─┐
+	DoubleFrame             // ╔═╗
+	RoundedFrame            // ╭─╮
+	HeavyFrame              // ┏━┓
+	ASCIIFrame              // +-+
+)
+
+// FillPattern represents a texture painted inside the glyph bodies
+type FillPattern int
+
+const (
+	NoPattern       FillPattern = iota
+	StripePattern               // Horizontal stripes
+	CheckerPattern              // Checkerboard
+	HatchPattern                // Diagonal hatch
+	DitherPattern               // Ordered dither between two colors along the gradient direction
+	ScanlinePattern             // CRT scanlines, every other pixel row darkened
+)
+
+// PaletteMode represents how palette colors are assigned to the text
+type PaletteMode int
+
+const (
+	NoPalette      PaletteMode = iota
+	PalettePerChar             // Each character takes the next palette color
+	PalettePerWord             // Each word takes the next palette color
+)
+
+// PaletteOrder represents the order in which palette colors are used
+type PaletteOrder int
+
+const (
+	PaletteCycle  PaletteOrder = iota // Step through the palette in order, wrapping around
+	PaletteRandom                     // Pick palette colors at random, reproducibly from PaletteSeed
+)
+
+// Padding is the space between the text and the edge of its background, in cells
+type Padding struct {
+	Top    int
+	Right  int
+	Bottom int
+	Left   int
+}
+
 // RenderOptions contains all the options for rendering text
 type RenderOptions struct {
 	// Spacing options
@@ -83,17 +154,188 @@ type RenderOptions struct {
 	GradientDirection GradientDirection
 	UseGradient       bool
 
+	// Palette options (per-character or per-word colors, replacing the gradient)
+	PaletteMode  PaletteMode
+	PaletteOrder PaletteOrder
+	Palette      []string // Hex colors (default: RainbowPalette)
+	PaletteSeed  uint64   // Seed for PaletteRandom; the same seed gives the same colors
+
+	// Fill pattern options
+	Pattern      FillPattern // Texture inside the glyph bodies
+	PatternColor string      // Hex second color of the pattern (default: primary color darkened)
+	PatternSize  int         // Pattern cell size in pixels (1 to 8, 0 means DefaultPatternSize)
+
 	// Text scale
 	ScaleFactor float64 // 0.5: half size, 1.0: normal, 2.0: double, 4.0: quadruple
 
+	// Synthetic style options
+	BoldStrength int     // Pixels each glyph is thickened to the right (0 to 3)
+	ObliqueShear float64 // Rightward slant in columns per pixel row (0 to 1, e.g. 0.25)
+
+	// Layout transforms
+	VerticalLayout bool     // Stack glyphs top to bottom with LineSpacing between them
+	Rotation       Rotation // Clockwise rotation of the whole text block
+	FlipHorizontal bool     // Mirror the text block left to right
+	FlipVertical   bool     // Mirror the text block top to bottom
+
 	// Shadow options
 	ShadowEnabled          bool
 	ShadowHorizontalOffset int // -5 to 5
 	ShadowVerticalOffset   int // -5 to 5
 	ShadowStyle            ShadowStyle
 
+	// Glow options
+	GlowEnabled bool
+	GlowRadius  int    // Glow reach in pixels (1 to 6, 0 means DefaultGlowRadius)
+	GlowColor   string // Hex color of the glow (default: text color or gradient)
+
+	// Extrusion options (layers follow the shadow offsets)
+	ExtrusionEnabled   bool
+	ExtrusionNearColor string // Hex color of the layer nearest the text (default: text color darkened)
+	ExtrusionFarColor  string // Hex color of the farthest layer (default: text color darkened further)
+	LongShadow         bool   // Extend the layers along the shadow direction to the canvas edge
+
+	// Reflection options
+	ReflectionEnabled bool
+	ReflectionGap     int            // Blank rows between the text and its reflection (0 to 5)
+	ReflectionHeight  int            // Reflection height as a percentage of the text height (0 means 100)
+	ReflectionFade    ReflectionFade // How the reflection fades out
+	ReflectionColor   string         // Hex color the reflection blends toward (default black)
+
+	// Background and frame options
+	BackgroundColor             string // Hex background color (empty keeps the background transparent)
+	BackgroundGradientColor     string // Hex end color for a background gradient
+	BackgroundGradientDirection GradientDirection
+	Padding                     Padding    // Space around the text inside the background
+	Frame                       FrameStyle // Box-drawing border around the padded text
+	FrameColor                  string     // Hex color of the frame (default: text color)
+
+	// Fill character options
+	Fill FillChars // Replacement characters for blocks and shades (zero value keeps them)
+
+	// Custom effects
+	Effects []Effect // Effects run after the built-in ones, in order
+
 	// Multi-line text
-	TextLines []string
+	TextLines  []string
+	LineStyles map[int]LineStyle // Per-line overrides keyed by text line index
+}
+
+// LineStyle overrides the render options of a single text line, for example a
+// large headline above a small tagline. Zero values keep the block-wide options.
+type LineStyle struct {
+	Font          *Font          // Font of the line (nil keeps the block font)
+	ScaleFactor   float64        // Scale of the line (0 keeps the block scale)
+	TextColor     string         // Hex text color of the line
+	GradientColor string         // Hex gradient end color; enables a gradient for the line
+	Alignment     *TextAlignment // Alignment of the line within the block
+}
+
+// lineOptions returns the options and font for text line index, with that
+// line's style applied on top of the block-wide options
+func (opts RenderOptions) lineOptions(index int, fontData FontData) (RenderOptions, FontData) {
+	style, ok := opts.LineStyles[index]
+	if !ok {
+		return opts, fontData
+	}
+	if style.Font != nil {
+		fontData = style.Font.FontData
+	}
+	if style.ScaleFactor != 0 {
+		opts.ScaleFactor = style.ScaleFactor
+	}
+	if style.TextColor != "" {
+		opts.TextColor = style.TextColor
+		opts.UseGradient = false
+	}
+	if style.GradientColor != "" {
+		opts.GradientColor = style.GradientColor
+		opts.UseGradient = style.GradientColor != opts.TextColor
+	}
+	if style.Alignment != nil {
+		opts.Alignment = *style.Alignment
+	}
+	return opts, fontData
+}
+
+// FillChars remaps the block and shade characters of the rendered output to
+// other characters. A zero rune keeps the original character, so the zero
+// value leaves the output unchanged.
+type FillChars struct {
+	FullBlock   rune // Replaces █
+	UpperHalf   rune // Replaces ▀
+	LowerHalf   rune // Replaces ▄
+	LightShade  rune // Replaces ░
+	MediumShade rune // Replaces ▒
+	DarkShade   rune // Replaces ▓
+
+	// GlyphLetters fills each glyph with the character it was rendered from,
+	// so an "A" is drawn with 'A' characters. Half blocks become whole cells.
+	GlyphLetters bool
+}
+
+// ASCIIFillChars returns a FillChars preset that only produces ASCII output,
+// for targets such as serial consoles, BBSes and plain-text email.
+func ASCIIFillChars() FillChars {
+	return FillChars{
+		FullBlock:   '#',
+		UpperHalf:   '"',
+		LowerHalf:   ',',
+		LightShade:  '.',
+		MediumShade: ':',
+		DarkShade:   '%',
+	}
+}
+
+// remap returns the replacement for a rendered block or shade character
+func (f FillChars) remap(r rune) rune {
+	var replacement rune
+	switch r {
+	case '█':
+		replacement = f.FullBlock
+	case '▀':
+		replacement = f.UpperHalf
+	case '▄':
+		replacement = f.LowerHalf
+	case '░':
+		replacement = f.LightShade
+	case '▒':
+		replacement = f.MediumShade
+	case '▓':
+		replacement = f.DarkShade
+	}
+	if replacement == 0 {
+		return r
+	}
+	return replacement
+}
+
+// Originals returns a map from each replacement character back to the block or
+// shade character it stands for. Exporters use it to keep drawing the custom
+// characters as filled pixels. When several characters share a replacement,
+// the fuller one wins.
+func (f FillChars) Originals() map[rune]rune {
+	originals := make(map[rune]rune)
+	pairs := []struct {
+		replacement rune
+		original    rune
+	}{
+		{f.FullBlock, '█'},
+		{f.UpperHalf, '▀'},
+		{f.LowerHalf, '▄'},
+		{f.DarkShade, '▓'},
+		{f.MediumShade, '▒'},
+		{f.LightShade, '░'},
+	}
+	for _, pair := range pairs {
+		if pair.replacement == 0 {
+			continue
+		}
+		if _, exists := originals[pair.replacement]; !exists {
+			originals[pair.replacement] = pair.original
+		}
+	}
+	return originals
 }
 
 // DefaultRenderOptions returns RenderOptions with default values
@@ -116,16 +358,30 @@ func DefaultRenderOptions() RenderOptions {
 
 // Validation constants for RenderOptions
 const (
-	MinCharSpacing  = 0
-	MaxCharSpacing  = 10
-	MinWordSpacing  = 0
-	MaxWordSpacing  = 20
-	MinLineSpacing  = 0
-	MaxLineSpacing  = 10
-	MinScaleFactor  = 0.5 // 0.5x
-	MaxScaleFactor  = 4.0 // 4x
-	MinShadowOffset = -5
-	MaxShadowOffset = 5
+	MinCharSpacing      = 0
+	MaxCharSpacing      = 10
+	MinWordSpacing      = 0
+	MaxWordSpacing      = 20
+	MinLineSpacing      = 0
+	MaxLineSpacing      = 10
+	MinScaleFactor      = 0.5 // 0.5x
+	MaxScaleFactor      = 4.0 // 4x
+	MinShadowOffset     = -5
+	MaxShadowOffset     = 5
+	MinBoldStrength     = 0
+	MaxBoldStrength     = 3
+	MinObliqueShear     = 0.0
+	MaxObliqueShear     = 1.0
+	MinGlowRadius       = 0
+	MaxGlowRadius       = 6
+	MinPatternSize      = 0
+	MaxPatternSize      = 8
+	MinPadding          = 0
+	MaxPadding          = 20
+	MinReflectionGap    = 0
+	MaxReflectionGap    = 5
+	MinReflectionHeight = 0
+	MaxReflectionHeight = 100
 )
 
 // Validate checks if the RenderOptions are valid and returns an error if not
@@ -146,6 +402,40 @@ func (opts *RenderOptions) Validate() error {
 		return &ScaleValidationError{Field: "ScaleFactor", Value: opts.ScaleFactor, Min: MinScaleFactor, Max: MaxScaleFactor}
 	}
 
+	// Validate synthetic styles
+	if opts.BoldStrength < MinBoldStrength || opts.BoldStrength > MaxBoldStrength {
+		return &ValidationError{Field: "BoldStrength", Value: opts.BoldStrength, Min: MinBoldStrength, Max: MaxBoldStrength}
+	}
+	if opts.ObliqueShear < MinObliqueShear || opts.ObliqueShear > MaxObliqueShear {
+		return &ScaleValidationError{Field: "ObliqueShear", Value: opts.ObliqueShear, Min: MinObliqueShear, Max: MaxObliqueShear}
+	}
+
+	// Validate palette
+	if opts.PaletteMode < NoPalette || opts.PaletteMode > PalettePerWord {
+		return &ValidationError{Field: "PaletteMode", Value: int(opts.PaletteMode), Min: int(NoPalette), Max: int(PalettePerWord)}
+	}
+	if opts.PaletteOrder < PaletteCycle || opts.PaletteOrder > PaletteRandom {
+		return &ValidationError{Field: "PaletteOrder", Value: int(opts.PaletteOrder), Min: int(PaletteCycle), Max: int(PaletteRandom)}
+	}
+	for i, paletteColor := range opts.Palette {
+		if !isValidHexColor(paletteColor) {
+			return &ColorValidationError{Field: fmt.Sprintf("Palette[%d]", i), Value: paletteColor}
+		}
+	}
+
+	// Validate fill pattern
+	if opts.Pattern < NoPattern || opts.Pattern > ScanlinePattern {
+		return &ValidationError{Field: "Pattern", Value: int(opts.Pattern), Min: int(NoPattern), Max: int(ScanlinePattern)}
+	}
+	if opts.PatternSize < MinPatternSize || opts.PatternSize > MaxPatternSize {
+		return &ValidationError{Field: "PatternSize", Value: opts.PatternSize, Min: MinPatternSize, Max: MaxPatternSize}
+	}
+
+	// Validate rotation
+	if opts.Rotation < NoRotation || opts.Rotation > Rotate270 {
+		return &ValidationError{Field: "Rotation", Value: int(opts.Rotation), Min: int(NoRotation), Max: int(Rotate270)}
+	}
+
 	// Validate shadow offsets
 	if opts.ShadowHorizontalOffset < MinShadowOffset || opts.ShadowHorizontalOffset > MaxShadowOffset {
 		return &ValidationError{Field: "ShadowHorizontalOffset", Value: opts.ShadowHorizontalOffset, Min: MinShadowOffset, Max: MaxShadowOffset}
@@ -154,6 +444,44 @@ func (opts *RenderOptions) Validate() error {
 		return &ValidationError{Field: "ShadowVerticalOffset", Value: opts.ShadowVerticalOffset, Min: MinShadowOffset, Max: MaxShadowOffset}
 	}
 
+	// Validate glow
+	if opts.GlowRadius < MinGlowRadius || opts.GlowRadius > MaxGlowRadius {
+		return &ValidationError{Field: "GlowRadius", Value: opts.GlowRadius, Min: MinGlowRadius, Max: MaxGlowRadius}
+	}
+
+	// Validate reflection
+	if opts.ReflectionGap < MinReflectionGap || opts.ReflectionGap > MaxReflectionGap {
+		return &ValidationError{Field: "ReflectionGap", Value: opts.ReflectionGap, Min: MinReflectionGap, Max: MaxReflectionGap}
+	}
+	if opts.ReflectionHeight < MinReflectionHeight || opts.ReflectionHeight > MaxReflectionHeight {
+		return &ValidationError{Field: "ReflectionHeight", Value: opts.ReflectionHeight, Min: MinReflectionHeight, Max: MaxReflectionHeight}
+	}
+	if opts.ReflectionFade < FadeShade || opts.ReflectionFade > FadeShadeColor {
+		return &ValidationError{Field: "ReflectionFade", Value: int(opts.ReflectionFade), Min: int(FadeShade), Max: int(FadeShadeColor)}
+	}
+
+	// Validate padding and frame
+	paddings := []struct {
+		field string
+		value int
+	}{
+		{"Padding.Top", opts.Padding.Top},
+		{"Padding.Right", opts.Padding.Right},
+		{"Padding.Bottom", opts.Padding.Bottom},
+		{"Padding.Left", opts.Padding.Left},
+	}
+	for _, p := range paddings {
+		if p.value < MinPadding || p.value > MaxPadding {
+			return &ValidationError{Field: p.field, Value: p.value, Min: MinPadding, Max: MaxPadding}
+		}
+	}
+	if opts.Frame < NoFrame || opts.Frame > ASCIIFrame {
+		return &ValidationError{Field: "Frame", Value: int(opts.Frame), Min: int(NoFrame), Max: int(ASCIIFrame)}
+	}
+	if opts.BackgroundGradientDirection < UpDown || opts.BackgroundGradientDirection > RightLeft {
+		return &ValidationError{Field: "BackgroundGradientDirection", Value: int(opts.BackgroundGradientDirection), Min: int(UpDown), Max: int(RightLeft)}
+	}
+
 	// Validate alignment
 	if opts.Alignment < LeftAlign || opts.Alignment > RightAlign {
 		return &ValidationError{Field: "Alignment", Value: int(opts.Alignment), Min: int(LeftAlign), Max: int(RightAlign)}
@@ -176,6 +504,65 @@ func (opts *RenderOptions) Validate() error {
 	if opts.UseGradient && !isValidHexColor(opts.GradientColor) {
 		return &ColorValidationError{Field: "GradientColor", Value: opts.GradientColor}
 	}
+	if opts.BackgroundColor != "" && !isValidHexColor(opts.BackgroundColor) {
+		return &ColorValidationError{Field: "BackgroundColor", Value: opts.BackgroundColor}
+	}
+	if opts.BackgroundGradientColor != "" && !isValidHexColor(opts.BackgroundGradientColor) {
+		return &ColorValidationError{Field: "BackgroundGradientColor", Value: opts.BackgroundGradientColor}
+	}
+	if opts.FrameColor != "" && !isValidHexColor(opts.FrameColor) {
+		return &ColorValidationError{Field: "FrameColor", Value: opts.FrameColor}
+	}
+	if opts.PatternColor != "" && !isValidHexColor(opts.PatternColor) {
+		return &ColorValidationError{Field: "PatternColor", Value: opts.PatternColor}
+	}
+	if opts.GlowColor != "" && !isValidHexColor(opts.GlowColor) {
+		return &ColorValidationError{Field: "GlowColor", Value: opts.GlowColor}
+	}
+	if opts.ExtrusionNearColor != "" && !isValidHexColor(opts.ExtrusionNearColor) {
+		return &ColorValidationError{Field: "ExtrusionNearColor", Value: opts.ExtrusionNearColor}
+	}
+	if opts.ExtrusionFarColor != "" && !isValidHexColor(opts.ExtrusionFarColor) {
+		return &ColorValidationError{Field: "ExtrusionFarColor", Value: opts.ExtrusionFarColor}
+	}
+	if opts.ReflectionColor != "" && !isValidHexColor(opts.ReflectionColor) {
+		return &ColorValidationError{Field: "ReflectionColor", Value: opts.ReflectionColor}
+	}
+
+	// Validate line styles
+	for index, style := range opts.LineStyles {
+		field := fmt.Sprintf("LineStyles[%d]", index)
+		if style.ScaleFactor != 0 && (style.ScaleFactor < MinScaleFactor || style.ScaleFactor > MaxScaleFactor) {
+			return &ScaleValidationError{Field: field + ".ScaleFactor", Value: style.ScaleFactor, Min: MinScaleFactor, Max: MaxScaleFactor}
+		}
+		if style.TextColor != "" && !isValidHexColor(style.TextColor) {
+			return &ColorValidationError{Field: field + ".TextColor", Value: style.TextColor}
+		}
+		if style.GradientColor != "" && !isValidHexColor(style.GradientColor) {
+			return &ColorValidationError{Field: field + ".GradientColor", Value: style.GradientColor}
+		}
+		if style.Alignment != nil && (*style.Alignment < LeftAlign || *style.Alignment > RightAlign) {
+			return &ValidationError{Field: field + ".Alignment", Value: int(*style.Alignment), Min: int(LeftAlign), Max: int(RightAlign)}
+		}
+	}
+
+	// Validate fill characters
+	fillChars := []struct {
+		field string
+		value rune
+	}{
+		{"Fill.FullBlock", opts.Fill.FullBlock},
+		{"Fill.UpperHalf", opts.Fill.UpperHalf},
+		{"Fill.LowerHalf", opts.Fill.LowerHalf},
+		{"Fill.LightShade", opts.Fill.LightShade},
+		{"Fill.MediumShade", opts.Fill.MediumShade},
+		{"Fill.DarkShade", opts.Fill.DarkShade},
+	}
+	for _, fc := range fillChars {
+		if fc.value != 0 && !isValidFillChar(fc.value) {
+			return &FillCharValidationError{Field: fc.field, Value: fc.value}
+		}
+	}
 
 	return nil
 }
@@ -214,6 +601,21 @@ func (e *ColorValidationError) Error() string {
 	return fmt.Sprintf("invalid %s: %s (must be a valid hex color like #FFFFFF)", e.Field, e.Value)
 }
 
+// FillCharValidationError represents a fill character validation error
+type FillCharValidationError struct {
+	Field string
+	Value rune
+}
+
+func (e *FillCharValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %q (must be a printable, non-space character)", e.Field, e.Value)
+}
+
+// isValidFillChar checks if a rune can be used as a fill character
+func isValidFillChar(r rune) bool {
+	return r != ' ' && unicode.IsPrint(r)
+}
+
 // isValidHexColor checks if a string is a valid hex color
 func isValidHexColor(color string) bool {
 	if len(color) != 7 || color[0] != '#' {
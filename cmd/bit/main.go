@@ -1,51 +1,194 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"unicode"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/paulilaaso/bit/ansifonts"
+	"github.com/paulilaaso/bit/export"
 	"github.com/paulilaaso/bit/internal/ui"
 )
 
+// typewriterHoldDelay is how long a typewriter GIF shows the finished text,
+// in hundredths of a second
+const typewriterHoldDelay = 150
+
+// lineStyleFlags collects the values of the repeatable -line flag
+type lineStyleFlags []string
+
+func (f *lineStyleFlags) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *lineStyleFlags) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 func main() {
 	// Define CLI flags
 	var fontName string
 	var textColor string
 	var gradientColor string
 	var gradientDirection string
+	var palette string
+	var paletteBy string
+	var seed int64
+	var pattern string
+	var patternColor string
+	var patternSize int
 	var charSpacing int
 	var wordSpacing int
 	var lineSpacing int
 	var scaleInt int
+	var boldStrength int
+	var obliqueShear float64
+	var vertical bool
+	var rotation int
+	var flip string
 	var shadowEnabled bool
 	var shadowH int
 	var shadowV int
 	var shadowStyle int
+	var glow bool
+	var glowRadius int
+	var glowColor string
+	var extrude bool
+	var extrudeNear string
+	var extrudeFar string
+	var longShadow bool
+	var reflect bool
+	var reflectGap int
+	var reflectHeight int
+	var reflectFade string
+	var reflectColor string
+	var bgColor string
+	var bgGradient string
+	var bgDirection string
+	var padding string
+	var frame string
+	var frameColor string
 	var alignment string
+	var lineStyles lineStyleFlags
+	var fitBox string
+	var fitFonts string
+	var wrap bool
 	var list bool
 	var version bool
 	var loadFontPath string
+	var asciiMode bool
+	var fillChars string
+	var shadeFillChars string
+	var glyphFill bool
+	var outputPath string
+	var outputFormat string
+	var htmlFragment bool
+	var htmlClasses bool
+	var animate string
+	var frames int
+	var delay int
+	var loop int
+	var ans16 bool
+	var ansCP437 bool
+	var title string
+	var author string
+	var pngPixel int
+	var pngScale int
+	var pngBackground string
+	var pngPadding int
+	var pngDither bool
+	var templatePath string
+	var goPackage string
+	var goName string
+	var noColorFallback bool
+
+	// Register the user's templates first, so -format lists their formats
+	if dir, err := export.DefaultTemplateDir(); err == nil {
+		if err := export.LoadTemplateDir(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
 
 	flag.StringVar(&fontName, "font", "", "Font name to use (default: first available font)")
 	flag.StringVar(&textColor, "color", "", "Text color: ANSI code (31) or hex (#FF0000)")
 	flag.StringVar(&gradientColor, "gradient", "", "Gradient end color: ANSI code (34) or hex (#0000FF)")
 	flag.StringVar(&gradientDirection, "direction", "down", "Gradient direction: down, up, right, left")
+	flag.StringVar(&palette, "palette", "", "Color each letter from a palette: rainbow, or comma-separated ANSI codes/hex (31,33,#00FF00)")
+	flag.StringVar(&paletteBy, "palette-by", "char", "Palette step: char (each letter) or word (each word)")
+	flag.Int64Var(&seed, "seed", -1, "Pick palette colors at random with this seed (0 or more); the same seed repeats the colors")
+	flag.StringVar(&pattern, "pattern", "", "Fill pattern inside the glyphs: stripes, checker, hatch, dither, scanline")
+	flag.StringVar(&patternColor, "pattern-color", "", "Second pattern color: ANSI code or hex (default: darkened text color, or -gradient for dither)")
+	flag.IntVar(&patternSize, "pattern-size", ansifonts.DefaultPatternSize, "Pattern cell size in pixels (1 to 8)")
 	flag.IntVar(&charSpacing, "char-spacing", 2, "Character spacing (0 to 10)")
 	flag.IntVar(&wordSpacing, "word-spacing", 2, "Word spacing (0 to 20)")
 	flag.IntVar(&lineSpacing, "line-spacing", 1, "Line spacing (0 to 10)")
 	flag.IntVar(&scaleInt, "scale", 0, "Text scale: -1 (0.5x), 0 (1x), 1 (2x), 2 (4x)")
+	flag.IntVar(&boldStrength, "bold", 0, "Synthetic bold: pixels to thicken each glyph (0 to 3)")
+	flag.Float64Var(&obliqueShear, "oblique", 0, "Synthetic italic slant in columns per pixel row (0 to 1, e.g. 0.25)")
+	flag.BoolVar(&vertical, "vertical", false, "Stack glyphs vertically, one column per line")
+	flag.IntVar(&rotation, "rotate", 0, "Rotate clockwise: 0, 90, 180, 270")
+	flag.StringVar(&flip, "flip", "", "Mirror the text: h (horizontal), v (vertical), hv (both)")
 	flag.BoolVar(&shadowEnabled, "shadow", false, "Enable shadow effect")
 	flag.IntVar(&shadowH, "shadow-h", 1, "Shadow horizontal offset (-5 to 5)")
 	flag.IntVar(&shadowV, "shadow-v", 1, "Shadow vertical offset (-5 to 5)")
 	flag.IntVar(&shadowStyle, "shadow-style", 1, "Shadow style: 0 (light), 1 (medium), 2 (dark)")
+	flag.BoolVar(&glow, "glow", false, "Neon glow around the text using ░▒▓ shades")
+	flag.IntVar(&glowRadius, "glow-radius", ansifonts.DefaultGlowRadius, "Glow reach in pixels (1 to 6)")
+	flag.StringVar(&glowColor, "glow-color", "", "Glow color: ANSI code or hex (default: text color)")
+	flag.BoolVar(&extrude, "extrude", false, "3D extrusion: fill layers along the shadow offsets")
+	flag.StringVar(&extrudeNear, "extrude-near", "", "Extrusion color nearest the text: ANSI code or hex (default: darkened text color)")
+	flag.StringVar(&extrudeFar, "extrude-far", "", "Extrusion color farthest from the text: ANSI code or hex")
+	flag.BoolVar(&longShadow, "long-shadow", false, "Extend the extrusion along the shadow direction to the edge")
+	flag.BoolVar(&reflect, "reflect", false, "Add a fading reflection below the text")
+	flag.IntVar(&reflectGap, "reflect-gap", 0, "Rows between the text and its reflection (0 to 5)")
+	flag.IntVar(&reflectHeight, "reflect-height", 100, "Reflection height as a percentage of the text (1 to 100)")
+	flag.StringVar(&reflectFade, "reflect-fade", "shade", "Reflection fade: shade, color, both")
+	flag.StringVar(&reflectColor, "reflect-color", "", "Color the reflection fades toward: ANSI code or hex (default black)")
+	flag.StringVar(&bgColor, "bg", "", "Background color: ANSI code (44 or 34) or hex (default: transparent)")
+	flag.StringVar(&bgGradient, "bg-gradient", "", "Background gradient end color: ANSI code (45 or 35) or hex")
+	flag.StringVar(&bgDirection, "bg-direction", "down", "Background gradient direction: down, up, right, left")
+	flag.StringVar(&padding, "padding", "", "Padding around the text: all, vertical,horizontal, or top,right,bottom,left")
+	flag.StringVar(&frame, "frame", "", "Frame style: single, double, rounded, heavy, ascii")
+	flag.StringVar(&frameColor, "frame-color", "", "Frame color: ANSI code or hex (default: text color)")
 	flag.StringVar(&alignment, "align", "center", "Text alignment: left, center, right")
+	flag.Var(&lineStyles, "line", "Style one text line, repeatable: N:font=NAME,scale=0.5|1|2|4,color=C,gradient=C,align=left|center|right")
+	flag.StringVar(&fitBox, "fit", "", "Render as large as fits in a box of WIDTHxHEIGHT cells (e.g. 80x10); overrides -scale")
+	flag.StringVar(&fitFonts, "fit-fonts", "", "Candidate fonts for -fit: comma-separated names, or all (default: -font)")
+	flag.BoolVar(&wrap, "wrap", false, "Let -fit wrap lines at spaces")
 	flag.BoolVar(&list, "list", false, "List all available fonts")
 	flag.BoolVar(&version, "version", false, "Show version information")
 	flag.StringVar(&loadFontPath, "load", "", "Path to a custom font file (.bit) OR a directory of fonts")
+	flag.BoolVar(&asciiMode, "ascii", false, "ASCII-only output: draw blocks and shades with # \" , . : %")
+	flag.StringVar(&fillChars, "fill", "", "Characters replacing █▀▄: one for all three, or three in that order")
+	flag.StringVar(&shadeFillChars, "shade-fill", "", "Characters replacing ░▒▓: one for all three, or three in that order")
+	flag.BoolVar(&glyphFill, "glyph-fill", false, "Draw each glyph with its own letter")
+	flag.StringVar(&outputPath, "o", "", "Write the rendering to a file, in the format of its extension or -format")
+	flag.StringVar(&outputFormat, "format", "", "Export format for -o or standard output: "+strings.ToLower(strings.Join(export.NewExportManager().GetFormatNames(), ", ")))
+	flag.BoolVar(&htmlFragment, "html-fragment", false, "HTML export: emit only the <pre> block for embedding")
+	flag.BoolVar(&htmlClasses, "html-classes", false, "HTML export: color spans with CSS classes instead of inline styles")
+	flag.StringVar(&animate, "animate", "", "GIF export: animate the text with gradient (rotation), typewriter or shadow (sweep)")
+	flag.IntVar(&frames, "frames", ansifonts.DefaultAnimationFrames, "GIF export: frames of the gradient and shadow animations")
+	flag.IntVar(&delay, "delay", export.DefaultGIFDelay, "GIF export: delay between frames in hundredths of a second")
+	flag.IntVar(&loop, "loop", 0, "GIF export: times to repeat the animation, 0 forever, -1 to play once")
+	flag.BoolVar(&ans16, "ans-16", false, "ANS export: reduce colors to the 16 classic SGR colors")
+	flag.BoolVar(&ansCP437, "ans-cp437", false, "ANS export: encode characters in CP437 instead of UTF-8")
+	flag.StringVar(&title, "title", "", "ANS export: SAUCE title (default: the text)")
+	flag.StringVar(&author, "author", "", "ANS export: SAUCE author (default: the font's author)")
+	flag.IntVar(&pngPixel, "png-pixel", 0, "PNG export: pixel-perfect square pixels of this size (default: 16x32 cells)")
+	flag.IntVar(&pngScale, "png-scale", 1, "PNG export: whole-number factor to enlarge the image by")
+	flag.StringVar(&pngBackground, "png-bg", "", "PNG export: background color, ANSI code or hex (default: transparent)")
+	flag.IntVar(&pngPadding, "png-padding", 0, "PNG export: pixels of background around the text")
+	flag.BoolVar(&pngDither, "png-dither", false, "PNG export: draw ░▒▓ as dither patterns instead of darkened fills")
+	flag.StringVar(&goPackage, "go-package", "", "GO export: generate a package of this name with a banner constant and Print function instead of a main program")
+	flag.StringVar(&goName, "go-name", export.DefaultGoIdentifier, "GO export: exported name of the banner constant with -go-package")
+	flag.BoolVar(&noColorFallback, "no-color-fallback", false, "MOTD, prompt and greeter exports: add a plain variant used when NO_COLOR is set")
+	flag.StringVar(&templatePath, "template", "", "Export with a code template file instead of -format (see README for its data and functions)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Bit - Terminal ANSI Logo Designer & Font Library\n\n")
@@ -66,10 +209,32 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  bit -font ithaca -color 31 \"Red\"                       # With font and color\n")
 		fmt.Fprintf(os.Stderr, "  bit -font ithaca -color \"#FF0000\" \"Red Hex\"            # Hex color\n")
 		fmt.Fprintf(os.Stderr, "  bit -font dogica -color 31 -gradient 34 \"Gradient\"     # Gradient\n")
+		fmt.Fprintf(os.Stderr, "  bit -palette rainbow \"Rainbow\"                           # One color per letter\n")
+		fmt.Fprintf(os.Stderr, "  bit -palette 31,33,34 -palette-by word -seed 7 \"A B C\"   # Seeded random colors per word\n")
+		fmt.Fprintf(os.Stderr, "  bit -color 92 -pattern scanline \"CRT\"                     # Pattern fill\n")
 		fmt.Fprintf(os.Stderr, "  bit -font pressstart -color 32 -shadow \"Shadow\"        # With shadow\n")
 		fmt.Fprintf(os.Stderr, "  bit -load ./myfont.bit \"Custom\"                        # Load custom font file\n")
 		fmt.Fprintf(os.Stderr, "  bit -load ./fonts/ -list                               # Load custom font directory\n")
 		fmt.Fprintf(os.Stderr, "  bit \"H\\+el\\+lo W\\-orld\"                                # Inline kerning: \\+ adds, \\- removes space\n")
+		fmt.Fprintf(os.Stderr, "  bit \"{color=#f00}Big{/} {font=dogica scale=2}Logo{/}\"     # Markup: per-span color, font, scale\n")
+		fmt.Fprintf(os.Stderr, "  bit -line 1:scale=2 -line 2:font=dogica,color=90 \"BIG\\nsmall tagline\"  # Per-line styles\n")
+		fmt.Fprintf(os.Stderr, "  bit -fit 80x10 -fit-fonts all -wrap \"Status Board\"              # Largest banner in 80x10\n")
+		fmt.Fprintf(os.Stderr, "  bit -bold 1 -oblique 0.25 \"Italic\"                     # Synthetic bold italic\n")
+		fmt.Fprintf(os.Stderr, "  bit -vertical -line-spacing 0 \"TMUX\"                   # Vertical text\n")
+		fmt.Fprintf(os.Stderr, "  bit -rotate 90 \"Side\"                                 # Rotated text\n")
+		fmt.Fprintf(os.Stderr, "  bit -glow -color 95 -glow-color 35 \"Neon\"                # Neon glow\n")
+		fmt.Fprintf(os.Stderr, "  bit -extrude -shadow-h 3 -shadow-v 2 -extrude-far 30 \"3D\" # 3D extrusion\n")
+		fmt.Fprintf(os.Stderr, "  bit -reflect -reflect-fade both -reflect-height 60 \"Lake\" # Water reflection\n")
+		fmt.Fprintf(os.Stderr, "  bit -bg 44 -padding 1,2 -frame rounded \"MOTD\"           # Background and frame\n")
+		fmt.Fprintf(os.Stderr, "  bit -ascii -shadow \"Serial\"                             # ASCII-only output\n")
+		fmt.Fprintf(os.Stderr, "  bit -fill @ -shade-fill : \"Custom\"                      # Custom fill characters\n")
+		fmt.Fprintf(os.Stderr, "  bit -o banner.html -html-fragment \"Docs\"                # Export to a file\n")
+		fmt.Fprintf(os.Stderr, "  bit -o logo.gif -animate gradient -gradient 34 \"Spin\"     # Animated GIF\n")
+		fmt.Fprintf(os.Stderr, "  bit -o logo.ans -ans-16 -ans-cp437 -author me \"BBS\"       # Classic ANSI art\n")
+		fmt.Fprintf(os.Stderr, "  bit -o logo.png -png-pixel 1 -png-scale 8 -png-bg 30 \"Pix\"  # Pixel-perfect PNG\n")
+		fmt.Fprintf(os.Stderr, "  bit -o banner/banner.go -go-package banner \"App\"         # Go package with Print(w)\n")
+		fmt.Fprintf(os.Stderr, "  bit -format updatemotd -no-color-fallback \"Host\" > 10-banner  # update-motd.d script\n")
+		fmt.Fprintf(os.Stderr, "  bit -template nim.tmpl -o banner.nim \"Nim\"              # Code from your own template\n")
 	}
 
 	flag.Parse()
@@ -132,8 +297,13 @@ func main() {
 	// Replace literal \n with actual newlines
 	text = strings.ReplaceAll(text, "\\n", "\n")
 
-	// Parse inline kerning markers (\+ and \-) from the text
-	text, customKerning := ansifonts.ParseInlineKerning(text)
+	// Markup such as {color=#f00}Big{/} is parsed at render time, together with
+	// its kerning markers; plain text only needs the inline kerning markers (\+ and \-)
+	useMarkup := ansifonts.HasMarkup(text)
+	var customKerning map[int]map[int]int
+	if !useMarkup {
+		text, customKerning = ansifonts.ParseInlineKerning(text)
+	}
 
 	// If no font specified, use the first available font
 	if fontName == "" {
@@ -178,6 +348,59 @@ func main() {
 		return defaultColor
 	}
 
+	// Helper function to parse background colors, which also accept the
+	// background ANSI codes (40-47, 100-107) of the foreground palette
+	parseBackgroundColor := func(colorInput string) string {
+		if code, err := strconv.Atoi(colorInput); err == nil && ((code >= 40 && code <= 47) || (code >= 100 && code <= 107)) {
+			colorInput = strconv.Itoa(code - 10)
+		}
+		return parseColor(colorInput, "")
+	}
+
+	// Helper function to parse padding: one value for all sides, two for
+	// vertical and horizontal, or four for top, right, bottom and left
+	parsePadding := func(paddingInput string) (ansifonts.Padding, bool) {
+		parts := strings.Split(paddingInput, ",")
+		values := make([]int, len(parts))
+		for i, part := range parts {
+			value, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || value < ansifonts.MinPadding || value > ansifonts.MaxPadding {
+				fmt.Fprintf(os.Stderr, "Warning: Invalid padding '%s', values must be between %d and %d\n", paddingInput, ansifonts.MinPadding, ansifonts.MaxPadding)
+				return ansifonts.Padding{}, false
+			}
+			values[i] = value
+		}
+		switch len(values) {
+		case 1:
+			return ansifonts.Padding{Top: values[0], Right: values[0], Bottom: values[0], Left: values[0]}, true
+		case 2:
+			return ansifonts.Padding{Top: values[0], Right: values[1], Bottom: values[0], Left: values[1]}, true
+		case 4:
+			return ansifonts.Padding{Top: values[0], Right: values[1], Bottom: values[2], Left: values[3]}, true
+		}
+		fmt.Fprintf(os.Stderr, "Warning: Invalid padding '%s', expected 1, 2 or 4 values\n", paddingInput)
+		return ansifonts.Padding{}, false
+	}
+
+	// Helper function to parse fill characters (one for all three, or three in order)
+	parseFill := func(fillInput string, flagName string) ([3]rune, bool) {
+		runes := []rune(fillInput)
+		if len(runes) == 1 {
+			runes = []rune{runes[0], runes[0], runes[0]}
+		}
+		if len(runes) != 3 {
+			fmt.Fprintf(os.Stderr, "Warning: -%s expects 1 or 3 characters, got '%s', ignoring\n", flagName, fillInput)
+			return [3]rune{}, false
+		}
+		for _, r := range runes {
+			if r == ' ' || !unicode.IsPrint(r) {
+				fmt.Fprintf(os.Stderr, "Warning: -%s contains a non-printable or space character, ignoring\n", flagName)
+				return [3]rune{}, false
+			}
+		}
+		return [3]rune{runes[0], runes[1], runes[2]}, true
+	}
+
 	// Convert scaleInt to actual scale factor
 	var scale float64
 	switch scaleInt {
@@ -194,15 +417,56 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Warning: Invalid scale value '%d', using default scale (1x)\n", scaleInt)
 	}
 
+	// Clamp synthetic styles to the supported range
+	if boldStrength < ansifonts.MinBoldStrength || boldStrength > ansifonts.MaxBoldStrength {
+		clamped := min(max(boldStrength, ansifonts.MinBoldStrength), ansifonts.MaxBoldStrength)
+		fmt.Fprintf(os.Stderr, "Warning: Invalid bold value '%d', must be between %d and %d, using %d\n", boldStrength, ansifonts.MinBoldStrength, ansifonts.MaxBoldStrength, clamped)
+		boldStrength = clamped
+	}
+	if obliqueShear < ansifonts.MinObliqueShear || obliqueShear > ansifonts.MaxObliqueShear {
+		clamped := min(max(obliqueShear, ansifonts.MinObliqueShear), ansifonts.MaxObliqueShear)
+		fmt.Fprintf(os.Stderr, "Warning: Invalid oblique value '%g', must be between %g and %g, using %g\n", obliqueShear, ansifonts.MinObliqueShear, ansifonts.MaxObliqueShear, clamped)
+		obliqueShear = clamped
+	}
+
 	// Build render options
 	options := ansifonts.RenderOptions{
 		CharSpacing:   charSpacing,
 		WordSpacing:   wordSpacing,
 		LineSpacing:   lineSpacing,
 		ScaleFactor:   scale,
+		BoldStrength:  boldStrength,
+		ObliqueShear:  obliqueShear,
 		CustomKerning: customKerning,
 	}
 
+	// Set layout transforms
+	options.VerticalLayout = vertical
+	switch rotation {
+	case 0:
+		options.Rotation = ansifonts.NoRotation
+	case 90:
+		options.Rotation = ansifonts.Rotate90
+	case 180:
+		options.Rotation = ansifonts.Rotate180
+	case 270:
+		options.Rotation = ansifonts.Rotate270
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: Invalid rotate value '%d', must be 0, 90, 180 or 270\n", rotation)
+	}
+	switch flip {
+	case "":
+	case "h":
+		options.FlipHorizontal = true
+	case "v":
+		options.FlipVertical = true
+	case "hv", "vh":
+		options.FlipHorizontal = true
+		options.FlipVertical = true
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: Invalid flip value '%s', must be h, v or hv\n", flip)
+	}
+
 	// Set alignment
 	switch alignment {
 	case "left":
@@ -218,25 +482,120 @@ func main() {
 	// Set colors (supports both ANSI codes and hex)
 	options.TextColor = parseColor(textColor, "#FFFFFF")
 
+	// Set per-line styles: N:key=value pairs separated by commas or spaces,
+	// where N counts text lines from 1
+	for _, lineStyle := range lineStyles {
+		lineNumber, attributes, found := strings.Cut(lineStyle, ":")
+		index, err := strconv.Atoi(strings.TrimSpace(lineNumber))
+		if !found || err != nil || index < 1 {
+			fmt.Fprintf(os.Stderr, "Warning: Invalid -line value '%s', expected N:key=value,...\n", lineStyle)
+			continue
+		}
+		var style ansifonts.LineStyle
+		for _, attribute := range strings.FieldsFunc(attributes, func(r rune) bool { return r == ',' || r == ' ' }) {
+			key, value, _ := strings.Cut(attribute, "=")
+			switch key {
+			case "font":
+				lineFont, err := ansifonts.LoadFont(value)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Error loading font '%s' for line %d: %v\n", value, index, err)
+					continue
+				}
+				style.Font = lineFont
+			case "scale":
+				lineScale, err := strconv.ParseFloat(value, 64)
+				if err != nil || (lineScale != 0.5 && lineScale != 1 && lineScale != 2 && lineScale != 4) {
+					fmt.Fprintf(os.Stderr, "Warning: Invalid scale '%s' for line %d, must be 0.5, 1, 2 or 4\n", value, index)
+					continue
+				}
+				style.ScaleFactor = lineScale
+			case "color":
+				style.TextColor = parseColor(value, "")
+			case "gradient":
+				style.GradientColor = parseColor(value, "")
+			case "align":
+				var lineAlignment ansifonts.TextAlignment
+				switch value {
+				case "left":
+					lineAlignment = ansifonts.LeftAlign
+				case "center":
+					lineAlignment = ansifonts.CenterAlign
+				case "right":
+					lineAlignment = ansifonts.RightAlign
+				default:
+					fmt.Fprintf(os.Stderr, "Warning: Invalid align '%s' for line %d, must be left, center or right\n", value, index)
+					continue
+				}
+				style.Alignment = &lineAlignment
+			default:
+				fmt.Fprintf(os.Stderr, "Warning: Unknown -line attribute '%s', ignoring\n", key)
+			}
+		}
+		if options.LineStyles == nil {
+			options.LineStyles = make(map[int]ansifonts.LineStyle)
+		}
+		options.LineStyles[index-1] = style
+	}
+
+	// Set gradient direction (also steers the dither pattern)
+	switch gradientDirection {
+	case "down":
+		options.GradientDirection = ansifonts.UpDown
+	case "up":
+		options.GradientDirection = ansifonts.DownUp
+	case "right":
+		options.GradientDirection = ansifonts.LeftRight
+	case "left":
+		options.GradientDirection = ansifonts.RightLeft
+	default:
+		options.GradientDirection = ansifonts.UpDown
+	}
+
 	// Set gradient
 	if gradientColor != "" {
 		options.GradientColor = parseColor(gradientColor, options.TextColor)
+		options.UseGradient = true
+	}
 
-		// Set gradient direction
-		switch gradientDirection {
-		case "down":
-			options.GradientDirection = ansifonts.UpDown
-		case "up":
-			options.GradientDirection = ansifonts.DownUp
-		case "right":
-			options.GradientDirection = ansifonts.LeftRight
-		case "left":
-			options.GradientDirection = ansifonts.RightLeft
-		default:
-			options.GradientDirection = ansifonts.UpDown
+	// Set palette (per-character or per-word colors)
+	if palette != "" || seed >= 0 {
+		options.PaletteMode = ansifonts.PalettePerChar
+		if paletteBy == "word" {
+			options.PaletteMode = ansifonts.PalettePerWord
+		} else if paletteBy != "char" {
+			fmt.Fprintf(os.Stderr, "Warning: Invalid palette-by value '%s', using char\n", paletteBy)
+		}
+		if palette != "" && palette != "rainbow" {
+			for _, entry := range strings.Split(palette, ",") {
+				options.Palette = append(options.Palette, parseColor(strings.TrimSpace(entry), options.TextColor))
+			}
 		}
+		if seed >= 0 {
+			options.PaletteOrder = ansifonts.PaletteRandom
+			options.PaletteSeed = uint64(seed)
+		}
+	}
 
-		options.UseGradient = true
+	// Set fill pattern
+	if pattern != "" {
+		switch pattern {
+		case "stripes":
+			options.Pattern = ansifonts.StripePattern
+		case "checker":
+			options.Pattern = ansifonts.CheckerPattern
+		case "hatch":
+			options.Pattern = ansifonts.HatchPattern
+		case "dither":
+			options.Pattern = ansifonts.DitherPattern
+		case "scanline":
+			options.Pattern = ansifonts.ScanlinePattern
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: Invalid pattern '%s', using none\n", pattern)
+		}
+		options.PatternSize = max(1, min(patternSize, ansifonts.MaxPatternSize))
+		if patternColor != "" {
+			options.PatternColor = parseColor(patternColor, options.TextColor)
+		}
 	}
 
 	// Set shadow
@@ -252,9 +611,294 @@ func main() {
 		options.ShadowStyle = ansifonts.ShadowStyle(shadowStyle)
 	}
 
+	// Set glow
+	if glow {
+		options.GlowEnabled = true
+		options.GlowRadius = max(1, min(glowRadius, ansifonts.MaxGlowRadius))
+		if glowColor != "" {
+			options.GlowColor = parseColor(glowColor, "")
+		}
+	}
+
+	// Set extrusion, which runs along the shadow offsets
+	if extrude || longShadow {
+		options.ExtrusionEnabled = true
+		options.LongShadow = longShadow
+		options.ShadowHorizontalOffset = shadowH
+		options.ShadowVerticalOffset = shadowV
+		if extrudeNear != "" {
+			options.ExtrusionNearColor = parseColor(extrudeNear, "")
+		}
+		if extrudeFar != "" {
+			options.ExtrusionFarColor = parseColor(extrudeFar, "")
+		}
+	}
+
+	// Set reflection
+	if reflect {
+		options.ReflectionEnabled = true
+		options.ReflectionGap = max(ansifonts.MinReflectionGap, min(reflectGap, ansifonts.MaxReflectionGap))
+		options.ReflectionHeight = max(1, min(reflectHeight, ansifonts.MaxReflectionHeight))
+
+		switch reflectFade {
+		case "shade":
+			options.ReflectionFade = ansifonts.FadeShade
+		case "color":
+			options.ReflectionFade = ansifonts.FadeColor
+		case "both":
+			options.ReflectionFade = ansifonts.FadeShadeColor
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: Invalid reflect-fade value '%s', using shade\n", reflectFade)
+			options.ReflectionFade = ansifonts.FadeShade
+		}
+
+		if reflectColor != "" {
+			options.ReflectionColor = parseColor(reflectColor, "#000000")
+		}
+	}
+
+	// Set background, padding and frame
+	if bgColor != "" {
+		options.BackgroundColor = parseBackgroundColor(bgColor)
+		if bgGradient != "" {
+			options.BackgroundGradientColor = parseBackgroundColor(bgGradient)
+		}
+		switch bgDirection {
+		case "down":
+			options.BackgroundGradientDirection = ansifonts.UpDown
+		case "up":
+			options.BackgroundGradientDirection = ansifonts.DownUp
+		case "right":
+			options.BackgroundGradientDirection = ansifonts.LeftRight
+		case "left":
+			options.BackgroundGradientDirection = ansifonts.RightLeft
+		default:
+			options.BackgroundGradientDirection = ansifonts.UpDown
+		}
+	}
+	if padding != "" {
+		if p, ok := parsePadding(padding); ok {
+			options.Padding = p
+		}
+	}
+	switch frame {
+	case "":
+	case "single":
+		options.Frame = ansifonts.SingleFrame
+	case "double":
+		options.Frame = ansifonts.DoubleFrame
+	case "rounded":
+		options.Frame = ansifonts.RoundedFrame
+	case "heavy":
+		options.Frame = ansifonts.HeavyFrame
+	case "ascii":
+		options.Frame = ansifonts.ASCIIFrame
+	default:
+		fmt.Fprintf(os.Stderr, "Warning: Invalid frame style '%s', must be single, double, rounded, heavy or ascii\n", frame)
+	}
+	if options.Frame != ansifonts.NoFrame && asciiMode {
+		// Box-drawing characters are not ASCII
+		options.Frame = ansifonts.ASCIIFrame
+	}
+	if frameColor != "" {
+		options.FrameColor = parseColor(frameColor, "")
+	}
+
+	// Set fill characters
+	if asciiMode {
+		options.Fill = ansifonts.ASCIIFillChars()
+	}
+	if fillChars != "" {
+		if runes, ok := parseFill(fillChars, "fill"); ok {
+			options.Fill.FullBlock, options.Fill.UpperHalf, options.Fill.LowerHalf = runes[0], runes[1], runes[2]
+		}
+	}
+	if shadeFillChars != "" {
+		if runes, ok := parseFill(shadeFillChars, "shade-fill"); ok {
+			options.Fill.LightShade, options.Fill.MediumShade, options.Fill.DarkShade = runes[0], runes[1], runes[2]
+		}
+	}
+	options.Fill.GlyphLetters = glyphFill
+
 	// Render and print
-	rendered := ansifonts.RenderTextWithOptions(text, font, options)
-	for _, line := range rendered {
-		fmt.Println(line)
+	var rendered []string
+	animationText, animationFont, animationOptions := text, font, options
+	if fitBox != "" && !useMarkup {
+		fit, ok := parseFitBox(fitBox)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Invalid -fit value '%s', expected WIDTHxHEIGHT such as 80x10\n", fitBox)
+			os.Exit(1)
+		}
+		fit.AllowWrap = wrap
+		fit.Fonts = loadFitFonts(fitFonts)
+		result, err := ansifonts.FitText(text, font, options, fit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (%s)\n", err, fitBox)
+			os.Exit(1)
+		}
+		rendered = result.Lines
+		animationText, animationFont, animationOptions = result.Text, result.Font, result.Options
+	} else if useMarkup {
+		if fitBox != "" {
+			fmt.Fprintf(os.Stderr, "Warning: -fit does not apply to markup, ignoring\n")
+		}
+		// Markup composes runs side by side, so whole-block layout is not applied
+		for _, ignored := range []struct {
+			name string
+			set  bool
+		}{
+			{"-vertical", options.VerticalLayout},
+			{"-rotate", options.Rotation != ansifonts.NoRotation},
+			{"-flip", options.FlipHorizontal || options.FlipVertical},
+			{"-reflect", options.ReflectionEnabled},
+			{"-line", len(options.LineStyles) > 0},
+		} {
+			if ignored.set {
+				fmt.Fprintf(os.Stderr, "Warning: %s does not apply to markup, ignoring\n", ignored.name)
+			}
+		}
+		rendered, err = ansifonts.RenderMarkup(text, font.FontData, options)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering markup: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		rendered = ansifonts.RenderTextWithOptions(text, font, options)
+	}
+
+	if outputPath == "" && outputFormat == "" && templatePath == "" {
+		for _, line := range rendered {
+			fmt.Println(line)
+		}
+		return
+	}
+
+	// Export in the requested format, to the file or to standard output
+	exporter := export.Lookup(outputFormat)
+	if templatePath != "" {
+		exporter, err = export.LoadTemplateFile(templatePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading template '%s': %v\n", templatePath, err)
+			os.Exit(1)
+		}
+	} else if outputFormat == "" {
+		exporter = export.LookupFilename(outputPath)
+	}
+	formatNames := strings.ToLower(strings.Join(export.NewExportManager().GetFormatNames(), ", "))
+	if exporter == nil && outputFormat != "" {
+		fmt.Fprintf(os.Stderr, "Error: Unknown export format '%s', use one of: %s\n", outputFormat, formatNames)
+		os.Exit(1)
+	} else if exporter == nil {
+		fmt.Fprintf(os.Stderr, "Error: Cannot tell the export format of '%s' from its extension, use -format with one of: %s\n", outputPath, formatNames)
+		os.Exit(1)
+	}
+	settings := export.DefaultOptions()
+	settings.PNG.PixelSize = pngPixel
+	settings.PNG.Scale = pngScale
+	settings.PNG.Padding = pngPadding
+	settings.PNG.DitherShades = pngDither
+	if pngBackground != "" {
+		settings.PNG.Background = parseBackgroundColor(pngBackground)
+	}
+
+	// Draw custom fill characters as the blocks and shades they stand for
+	fillOriginals := options.Fill.Originals()
+	settings.PNG.FillChars = fillOriginals
+	settings.GIF.FillChars = fillOriginals
+	settings.SVG.FillChars = fillOriginals
+	settings.HTML.Fragment = htmlFragment
+	settings.HTML.UseClasses = htmlClasses
+	settings.GIF.Delay = delay
+	settings.GIF.LoopCount = loop
+	settings.ANS.Colors16 = ans16
+	settings.ANS.CP437 = ansCP437
+	settings.ANS.Title = title
+	settings.ANS.Author = author
+	settings.Go.Package = goPackage
+	settings.Go.Identifier = goName
+	settings.Shell.NoColorFallback = noColorFallback
+
+	// A GIF holds the rendering as its only frame unless it is animated
+	result := export.Result{Lines: rendered, Text: text, Font: animationFont}
+	if useMarkup {
+		// Titles and templates show the text without its tags
+		result.Text, _ = ansifonts.MarkupText(text)
+	}
+	if animate != "" {
+		if !exporter.Animated() {
+			fmt.Fprintf(os.Stderr, "Error: -animate needs an animated export such as GIF (-o NAME.gif or -format gif)\n")
+			os.Exit(1)
+		}
+		if useMarkup {
+			fmt.Fprintf(os.Stderr, "Error: -animate does not apply to markup\n")
+			os.Exit(1)
+		}
+		animation, err := ansifonts.ParseAnimation(animate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		result.Frames = ansifonts.RenderAnimation(animationText, animationFont, animationOptions, animation, frames)
+		if animation == ansifonts.TypewriterReveal && len(result.Frames) > 0 {
+			// Hold the finished text before starting over
+			settings.GIF.Delays = make([]int, len(result.Frames))
+			settings.GIF.Delays[len(result.Frames)-1] = typewriterHoldDelay
+		}
+	}
+	content, err := exporter.Export(result, settings)
+	var warning *export.Warning
+	if errors.As(err, &warning) {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Error exporting %s: %v\n", exporter.Name(), err)
+		os.Exit(1)
+	}
+	if outputPath == "" {
+		os.Stdout.Write(content)
+		return
+	}
+	if err := os.WriteFile(outputPath, content, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing '%s': %v\n", outputPath, err)
+		os.Exit(1)
+	}
+}
+
+// parseFitBox parses a WIDTHxHEIGHT box for -fit. Either size may be 0 to
+// leave it unlimited.
+func parseFitBox(box string) (ansifonts.FitOptions, bool) {
+	widthText, heightText, found := strings.Cut(strings.ToLower(box), "x")
+	width, errWidth := strconv.Atoi(widthText)
+	height, errHeight := strconv.Atoi(heightText)
+	if !found || errWidth != nil || errHeight != nil || width < 0 || height < 0 || width+height == 0 {
+		return ansifonts.FitOptions{}, false
+	}
+	return ansifonts.FitOptions{Width: width, Height: height}, true
+}
+
+// loadFitFonts loads the candidate fonts named by -fit-fonts. An empty list
+// keeps the -font font, and "all" loads every available font.
+func loadFitFonts(names string) []*ansifonts.Font {
+	if names == "" {
+		return nil
+	}
+	var fontNames []string
+	if names == "all" {
+		var err error
+		if fontNames, err = ansifonts.ListFonts(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Error listing fonts: %v\n", err)
+		}
+	} else {
+		fontNames = strings.Split(names, ",")
+	}
+
+	var fonts []*ansifonts.Font
+	for _, name := range fontNames {
+		font, err := ansifonts.LoadFont(strings.TrimSpace(name))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Error loading font '%s': %v\n", name, err)
+			continue
+		}
+		fonts = append(fonts, font)
 	}
+	return fonts
 }
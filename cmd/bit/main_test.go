@@ -0,0 +1,160 @@
+// ABOUTME: End-to-end tests of the bit command line, running main in a subprocess.
+// ABOUTME: Verifies that flags reach the renderer and the exporters through the CLI settings.
+
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// runMainEnv makes the test binary run main instead of the tests
+const runMainEnv = "BIT_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runBit runs the command line with the given arguments and returns its
+// standard output and standard error
+func runBit(t *testing.T, args ...string) (string, string) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	// Keep user templates out of the registry
+	home := t.TempDir()
+	cmd.Env = append(os.Environ(), runMainEnv+"=1", "HOME="+home, "XDG_CONFIG_HOME="+home)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("bit %v: %v\n%s", args, err, stderr.String())
+	}
+	return stdout.String(), stderr.String()
+}
+
+// ansiCodes matches the color codes of the terminal output
+var ansiCodes = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func TestFit_RendersLargestSizeInBox(t *testing.T) {
+	// "Hello" in dogica is 7 rows tall at scale 1 and fits 80 columns at
+	// scale 2 only with the spacing tightened
+	tests := []struct {
+		box           string
+		width, height int
+	}{
+		{"80x14", 80, 14},
+		{"79x0", 79, 7},
+		{"0x13", 0, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.box, func(t *testing.T) {
+			stdout, _ := runBit(t, "-font", "dogica", "-fit", tt.box, "Hello")
+			lines := strings.Split(strings.TrimSuffix(ansiCodes.ReplaceAllString(stdout, ""), "\n"), "\n")
+			width := 0
+			for _, line := range lines {
+				width = max(width, utf8.RuneCountInString(strings.TrimRight(line, " ")))
+			}
+			if len(lines) != tt.height {
+				t.Errorf("expected %d rows, got %d:\n%s", tt.height, len(lines), stdout)
+			}
+			if tt.width > 0 && width > tt.width {
+				t.Errorf("%d columns overflow the box %s", width, tt.box)
+			}
+		})
+	}
+}
+
+func TestASCIIPNGExport_DrawsFillCharsAsBlocks(t *testing.T) {
+	// At half scale dogica's "A" starts with ",""", the ASCII stand-ins of ▄▀▀▀
+	path := filepath.Join(t.TempDir(), "a.png")
+	runBit(t, "-ascii", "-scale", "-1", "-font", "dogica", "-o", path, "A")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the export: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("PNG decode failed: %v", err)
+	}
+
+	// Default PNG cells are 16x32 pixels
+	opaque := func(x, y int) bool {
+		_, _, _, a := img.At(x, y).RGBA()
+		return a != 0
+	}
+	tests := []struct {
+		name        string
+		cellX       int
+		top, bottom bool
+	}{
+		{`"`, 1, true, false},
+		{",", 0, false, true},
+	}
+	for _, tt := range tests {
+		x := tt.cellX*16 + 8
+		if got := opaque(x, 8); got != tt.top {
+			t.Errorf("%s: expected top half opaque=%v, got %v", tt.name, tt.top, got)
+		}
+		if got := opaque(x, 24); got != tt.bottom {
+			t.Errorf("%s: expected bottom half opaque=%v, got %v", tt.name, tt.bottom, got)
+		}
+	}
+}
+
+func TestMarkup_WarnsAboutIgnoredLayoutFlags(t *testing.T) {
+	_, stderr := runBit(t, "-font", "dogica", "-reflect", "-rotate", "90", "-flip", "h", "-vertical", "{color=#FF0000}A{/}B")
+	for _, name := range []string{"-vertical", "-rotate", "-flip", "-reflect"} {
+		if !strings.Contains(stderr, "Warning: "+name+" does not apply to markup") {
+			t.Errorf("expected a warning about %s, got:\n%s", name, stderr)
+		}
+	}
+}
+
+func TestMarkupANSExport_TitleWithoutTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.ans")
+	runBit(t, "-font", "dogica", "-o", path, "{color=#FF0000}A{/}B")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the export: %v", err)
+	}
+	// The SAUCE record is the last 128 bytes, with the title after "SAUCE00"
+	sauce := data[len(data)-128:]
+	if title := strings.TrimRight(string(sauce[7:42]), " "); title != "AB" {
+		t.Errorf("expected SAUCE title %q, got %q", "AB", title)
+	}
+}
+
+func TestTmuxExport_FoldsTallArtWithWarning(t *testing.T) {
+	stdout, stderr := runBit(t, "-format", "tmux", "-font", "dogica", "A")
+	if !strings.Contains(stderr, "Warning: tmux fits 4 banner lines") {
+		t.Errorf("expected a warning about folded lines, got:\n%s", stderr)
+	}
+	if !strings.Contains(stdout, "set -g status 5\n") {
+		t.Errorf("expected 4 banner lines, got:\n%s", stdout)
+	}
+}
+
+func TestExport_FormatFromDoubleExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "header.nvim.lua")
+	runBit(t, "-font", "dogica", "-o", path, "A")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read the export: %v", err)
+	}
+	if !strings.Contains(string(data), "BitHeader") {
+		t.Errorf("expected a Neovim header, got:\n%s", data)
+	}
+}
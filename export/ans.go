@@ -0,0 +1,248 @@
+// ABOUTME: ANSI art generator that writes the rendered escape stream as a classic .ans file.
+// ABOUTME: Optionally converts to 16-color SGR and CP437, and appends a SAUCE metadata record.
+
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/paulilaaso/bit/ansifonts"
+)
+
+// SAUCE record constants, see https://www.acid.org/info/sauce/sauce.htm
+const (
+	sauceEOF          = 0x1A // End-of-file marker that hides the metadata from DOS-era viewers
+	sauceDataTypeChar = 1    // Character data type
+	sauceFileTypeANSi = 1    // ANSi file type of the character data type
+	sauceCommentWidth = 64   // Width of a comment line
+	sauceTitleWidth   = 35
+	sauceAuthorWidth  = 20
+	sauceGroupWidth   = 20
+	sauceFontWidth    = 22
+)
+
+// ANSVGAFont is the SAUCE font name of the IBM VGA font, which ANSI art
+// viewers use to draw CP437 files
+const ANSVGAFont = "IBM VGA"
+
+// ANSOptions contains configuration for .ans generation
+type ANSOptions struct {
+	Colors16 bool // Convert 24-bit colors to the 16 classic SGR colors (bright foregrounds use bold)
+	CP437    bool // Encode characters in code page 437 instead of UTF-8
+
+	// SAUCE metadata; text beyond the field widths is cut off
+	NoSAUCE  bool      // Leave out the SAUCE record
+	Title    string    // Title of the art (up to 35 characters)
+	Author   string    // Author of the art (up to 20 characters)
+	Group    string    // Group the author belongs to (up to 20 characters)
+	FontName string    // Name of the bit font, recorded in a SAUCE comment
+	Date     time.Time // Creation date (default: today)
+}
+
+// DefaultANSOptions returns default .ans generation options: the escape
+// stream as rendered, in 24-bit color and UTF-8, with a SAUCE record
+func DefaultANSOptions() ANSOptions {
+	return ANSOptions{}
+}
+
+// sgrColors are the 16 classic colors in SGR order, the 8 normal colors
+// (codes 30-37) then their bright variants (90-97), as bit renders the ANSI
+// codes, so text colored by code converts back to that code
+var sgrColors = func() [16]color.RGBA {
+	var colors [16]color.RGBA
+	for i := range 8 {
+		colors[i], _ = parseHexColor(ansifonts.ANSIColorMap[strconv.Itoa(30+i)])
+		colors[i+8], _ = parseHexColor(ansifonts.ANSIColorMap[strconv.Itoa(90+i)])
+	}
+	return colors
+}()
+
+// GenerateANS creates a .ans file from rendered ANSI lines: the lines joined
+// with CRLF, followed by a SAUCE record unless NoSAUCE is set.
+// Returns the file data as bytes or error.
+func GenerateANS(lines []string, options ANSOptions) ([]byte, error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no content to export")
+	}
+
+	var text strings.Builder
+	width := 0
+	for i, line := range lines {
+		if i > 0 {
+			text.WriteString("\r\n")
+		}
+		if options.Colors16 {
+			writeANS16Line(&text, line)
+		} else {
+			text.WriteString(line)
+		}
+		width = max(width, countVisibleChars(line))
+	}
+	text.WriteString("\x1b[0m\r\n")
+
+	var buf bytes.Buffer
+	if options.CP437 {
+		buf.Write(encodeCP437(text.String()))
+	} else {
+		buf.WriteString(text.String())
+	}
+	if options.NoSAUCE {
+		return buf.Bytes(), nil
+	}
+
+	fileSize := buf.Len()
+	buf.WriteByte(sauceEOF)
+	comments := 0
+	if options.FontName != "" {
+		// SAUCE has no field for the bit font, so it goes in a comment line
+		buf.WriteString("COMNT")
+		buf.Write(sauceField("Font: "+options.FontName, sauceCommentWidth, ' '))
+		comments = 1
+	}
+	writeSAUCERecord(&buf, options, fileSize, width, len(lines), comments)
+	return buf.Bytes(), nil
+}
+
+// writeSAUCERecord appends the 128-byte SAUCE record
+func writeSAUCERecord(buf *bytes.Buffer, options ANSOptions, fileSize, width, height, comments int) {
+	date := options.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	var fontName string
+	if options.CP437 {
+		fontName = ANSVGAFont
+	}
+
+	buf.WriteString("SAUCE00")
+	buf.Write(sauceField(options.Title, sauceTitleWidth, ' '))
+	buf.Write(sauceField(options.Author, sauceAuthorWidth, ' '))
+	buf.Write(sauceField(options.Group, sauceGroupWidth, ' '))
+	buf.WriteString(date.Format("20060102"))
+	binary.Write(buf, binary.LittleEndian, uint32(fileSize))
+	buf.WriteByte(sauceDataTypeChar)
+	buf.WriteByte(sauceFileTypeANSi)
+	binary.Write(buf, binary.LittleEndian, uint16(width))  // TInfo1: width in characters
+	binary.Write(buf, binary.LittleEndian, uint16(height)) // TInfo2: number of lines
+	binary.Write(buf, binary.LittleEndian, uint16(0))      // TInfo3
+	binary.Write(buf, binary.LittleEndian, uint16(0))      // TInfo4
+	buf.WriteByte(byte(comments))
+	buf.WriteByte(0) // TFlags
+	buf.Write(sauceField(fontName, sauceFontWidth, 0))
+}
+
+// sauceField encodes text in CP437 and pads or cuts it to width bytes
+func sauceField(text string, width int, pad byte) []byte {
+	field := encodeCP437(text)
+	if len(field) > width {
+		return field[:width]
+	}
+	return append(field, bytes.Repeat([]byte{pad}, width-len(field))...)
+}
+
+// ans16State is the color state of the 16-color stream: a foreground from
+// sgrColors and one of its 8 normal colors as background, -1 for the default
+type ans16State struct {
+	fg, bg int
+}
+
+// writeANS16Line writes a rendered line with its colors reduced to the 16
+// classic colors. Every change resets the attributes and sets them in full,
+// since bold, which brightens the foreground, has no portable reset of its own.
+func writeANS16Line(b *strings.Builder, line string) {
+	current := ans16State{fg: -1, bg: -1}
+	for _, cell := range parseANSILine(line) {
+		want := ans16State{fg: current.fg, bg: -1}
+		if cell.bg.A != 0 {
+			want.bg = nearestSGRColor(cell.bg, 8)
+		}
+		if cell.char != ' ' {
+			want.fg = nearestSGRColor(cell.fg, 16)
+		}
+		if want != current {
+			b.WriteString(want.sgr())
+			current = want
+		}
+		b.WriteRune(cell.char)
+	}
+	if current != (ans16State{fg: -1, bg: -1}) {
+		b.WriteString("\x1b[0m")
+	}
+}
+
+// sgr returns the escape sequence setting the state from a reset
+func (s ans16State) sgr() string {
+	params := []string{"0"}
+	if s.fg >= 8 {
+		params = append(params, "1")
+	}
+	if s.fg >= 0 {
+		params = append(params, fmt.Sprintf("3%d", s.fg%8))
+	}
+	if s.bg >= 0 {
+		params = append(params, fmt.Sprintf("4%d", s.bg))
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// nearestSGRColor returns the index of the closest of the first count sgrColors
+func nearestSGRColor(c color.RGBA, count int) int {
+	best, bestDistance := 0, -1
+	for i, sgr := range sgrColors[:count] {
+		dr := int(c.R) - int(sgr.R)
+		dg := int(c.G) - int(sgr.G)
+		db := int(c.B) - int(sgr.B)
+		if distance := dr*dr + dg*dg + db*db; bestDistance < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return best
+}
+
+// cp437High holds the characters of CP437 bytes 0x80 to 0xFF
+const cp437High = "ÇüéâäàåçêëèïîìÄÅÉæÆôöòûùÿÖÜ¢£¥₧ƒáíóúñÑªº¿⌐¬½¼¡«»" +
+	"░▒▓│┤╡╢╖╕╣║╗╝╜╛┐└┴┬├─┼╞╟╚╔╩╦╠═╬╧╨╤╥╙╘╒╓╫╪┘┌█▄▌▐▀" +
+	"αßΓπΣσµτΦΘΩδ∞φε∩≡±≥≤⌠⌡÷≈°∙·√ⁿ²■\u00a0"
+
+// cp437Bytes maps the characters of cp437High to their bytes
+var cp437Bytes = func() map[rune]byte {
+	table := make(map[rune]byte, 128)
+	i := 0x80
+	for _, r := range cp437High {
+		table[r] = byte(i)
+		i++
+	}
+	return table
+}()
+
+// cp437Substitutes maps characters missing from CP437 to the closest ones it has
+var cp437Substitutes = map[rune]rune{
+	'╭': '┌', '╮': '┐', '╰': '└', '╯': '┘', // Rounded frame corners
+	'┏': '┌', '┓': '┐', '┗': '└', '┛': '┘', '━': '─', '┃': '│', // Heavy frame
+}
+
+// encodeCP437 encodes text in code page 437. Control and ASCII characters
+// keep their bytes, and characters CP437 lacks become '?'.
+func encodeCP437(text string) []byte {
+	encoded := make([]byte, 0, utf8.RuneCountInString(text))
+	for _, r := range text {
+		if substitute, ok := cp437Substitutes[r]; ok {
+			r = substitute
+		}
+		if r < 0x80 {
+			encoded = append(encoded, byte(r))
+		} else if b, ok := cp437Bytes[r]; ok {
+			encoded = append(encoded, b)
+		} else {
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
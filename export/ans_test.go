@@ -0,0 +1,128 @@
+// ABOUTME: Tests for .ans generation from ANSI-colored text output.
+// ABOUTME: Verifies the escape stream, 16-color conversion, CP437 encoding and the SAUCE record.
+
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+// sauceRecord returns the trailing 128-byte SAUCE record of a .ans file
+func sauceRecord(t *testing.T, data []byte) []byte {
+	t.Helper()
+	if len(data) < 128 || !bytes.HasPrefix(data[len(data)-128:], []byte("SAUCE00")) {
+		t.Fatalf("expected a trailing SAUCE record, got %q", data)
+	}
+	return data[len(data)-128:]
+}
+
+func TestGenerateANS_EmptyInput(t *testing.T) {
+	_, err := GenerateANS([]string{}, DefaultANSOptions())
+	if err == nil {
+		t.Error("expected error for empty input, got nil")
+	}
+}
+
+func TestGenerateANS_KeepsEscapeStream(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m█\x1b[0m", "\x1b[38;2;0;0;255m▀\x1b[0m"}
+	options := DefaultANSOptions()
+	options.NoSAUCE = true
+
+	data, err := GenerateANS(lines, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "\x1b[38;2;255;0;0m█\x1b[0m\r\n\x1b[38;2;0;0;255m▀\x1b[0m\x1b[0m\r\n"
+	if string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, data)
+	}
+}
+
+func TestGenerateANS_Colors16AndCP437(t *testing.T) {
+	// Bit's bright red (91) and red (31), then the blue of its background code 44
+	lines := []string{"\x1b[38;2;255;153;153m█\x1b[0m\x1b[38;2;205;49;49m▄\x1b[0m\x1b[48;2;36;114;200m \x1b[0m"}
+	options := DefaultANSOptions()
+	options.Colors16 = true
+	options.CP437 = true
+	options.NoSAUCE = true
+
+	data, err := GenerateANS(lines, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Bright red is bold red; the full and lower half blocks are bytes 0xDB and 0xDC
+	expected := "\x1b[0;1;31m\xdb\x1b[0;31m\xdc\x1b[0;31;44m \x1b[0m\x1b[0m\r\n"
+	if string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, data)
+	}
+}
+
+func TestGenerateANS_CP437Substitutes(t *testing.T) {
+	options := DefaultANSOptions()
+	options.CP437 = true
+	options.NoSAUCE = true
+
+	data, err := GenerateANS([]string{"╭─╮é☺"}, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bytes.HasPrefix(data, []byte("\xda\xc4\xbf\x82?")) {
+		t.Errorf("expected rounded corners as square ones and unknown characters as '?', got %q", data)
+	}
+}
+
+func TestGenerateANS_SAUCE(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m███\x1b[0m", "\x1b[38;2;255;0;0m█\x1b[0m"}
+	options := DefaultANSOptions()
+	options.CP437 = true
+	options.Title = "Logo"
+	options.Author = "A Very Long Author Name Indeed"
+	options.FontName = "dogica"
+	options.Date = time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	data, err := GenerateANS(lines, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	record := sauceRecord(t, data)
+	if title := string(record[7:42]); title != "Logo"+string(bytes.Repeat([]byte(" "), 31)) {
+		t.Errorf("unexpected title %q", title)
+	}
+	if author := string(record[42:62]); author != "A Very Long Author N" {
+		t.Errorf("expected the author cut to 20 characters, got %q", author)
+	}
+	if date := string(record[82:90]); date != "20240309" {
+		t.Errorf("unexpected date %q", date)
+	}
+	if record[94] != 1 || record[95] != 1 {
+		t.Errorf("expected the character data type and ANSi file type, got %d and %d", record[94], record[95])
+	}
+	if width := binary.LittleEndian.Uint16(record[96:]); width != 3 {
+		t.Errorf("expected width 3, got %d", width)
+	}
+	if height := binary.LittleEndian.Uint16(record[98:]); height != 2 {
+		t.Errorf("expected 2 lines, got %d", height)
+	}
+	if record[104] != 1 {
+		t.Errorf("expected 1 comment line, got %d", record[104])
+	}
+	if font := string(bytes.TrimRight(record[106:], "\x00")); font != ANSVGAFont {
+		t.Errorf("expected the %q font, got %q", ANSVGAFont, font)
+	}
+
+	// The file size excludes the EOF marker, the comment block and the record
+	fileSize := int(binary.LittleEndian.Uint32(record[90:]))
+	if len(data) != fileSize+1+5+64+128 || data[fileSize] != 0x1A {
+		t.Fatalf("expected the EOF marker at the file size, got %q", data[fileSize:])
+	}
+	if comment := data[fileSize+1:]; !bytes.HasPrefix(comment, []byte("COMNTFont: dogica ")) {
+		t.Errorf("expected a comment naming the font, got %q", comment)
+	}
+}
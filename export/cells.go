@@ -0,0 +1,150 @@
+// ABOUTME: Parses ANSI-colored lines into character cells with their colors.
+// ABOUTME: Shared by the exporters that redraw rendered text in other formats.
+
+package export
+
+import (
+	"fmt"
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Regex patterns for parsing ANSI escape sequences
+var (
+	// Matches 24-bit foreground color: ESC[38;2;R;G;Bm
+	colorRegex = regexp.MustCompile(`\x1b\[38;2;(\d+);(\d+);(\d+)m`)
+	// Matches 24-bit background color: ESC[48;2;R;G;Bm
+	bgColorRegex = regexp.MustCompile(`\x1b\[48;2;(\d+);(\d+);(\d+)m`)
+)
+
+// ansiCell is a character of a rendered line with the colors it is drawn in
+type ansiCell struct {
+	char rune
+	fg   color.RGBA // Foreground color, white until a color is set
+	bg   color.RGBA // Background color, zero (transparent) when none is set
+}
+
+// parseANSILine splits a rendered line into cells, tracking the 24-bit
+// foreground and background colors set by its escape sequences
+func parseANSILine(line string) []ansiCell {
+	// Default color (white, fully opaque) on a transparent background
+	currentColor := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	var currentBg color.RGBA
+	var cells []ansiCell
+
+	// Process line character by character, tracking ANSI state
+	i := 0
+	lineBytes := []byte(line)
+
+	for i < len(lineBytes) {
+		// Check for ANSI escape sequence (starts with ESC [)
+		if lineBytes[i] == 0x1b && i+1 < len(lineBytes) && lineBytes[i+1] == '[' {
+			// Find end of escape sequence
+			seqStart := i
+			i += 2 // Skip ESC [
+
+			// Scan for terminator (letter)
+			for i < len(lineBytes) && !isAnsiTerminator(lineBytes[i]) {
+				i++
+			}
+			if i < len(lineBytes) {
+				i++ // Include terminator
+			}
+
+			// Parse the sequence
+			seq := string(lineBytes[seqStart:i])
+			if matches := colorRegex.FindStringSubmatch(seq); matches != nil {
+				currentColor = parseRGBMatch(matches)
+			} else if matches := bgColorRegex.FindStringSubmatch(seq); matches != nil {
+				currentBg = parseRGBMatch(matches)
+			} else if seq == "\x1b[0m" {
+				// Reset clears the background; the foreground stays until changed
+				currentBg = color.RGBA{}
+			}
+			continue
+		}
+
+		// Decode UTF-8 character
+		r, size := utf8.DecodeRune(lineBytes[i:])
+		if r == utf8.RuneError && size == 1 {
+			// Invalid UTF-8, skip byte
+			i++
+			continue
+		}
+
+		cells = append(cells, ansiCell{char: r, fg: currentColor, bg: currentBg})
+		i += size
+	}
+	return cells
+}
+
+// parseRGBMatch converts the R, G and B groups of a color regex match to an opaque color
+func parseRGBMatch(matches []string) color.RGBA {
+	r, _ := strconv.Atoi(matches[1])
+	g, _ := strconv.Atoi(matches[2])
+	b, _ := strconv.Atoi(matches[3])
+	return color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}
+}
+
+// parseHexColor parses a "#RRGGBB" or "RRGGBB" color
+func parseHexColor(hex string) (color.RGBA, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(hex, "#")) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid hex color %q", hex)
+	}
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 255}, nil
+}
+
+// hexColor formats a color as a "#rrggbb" string for SVG and CSS
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// isAnsiTerminator checks if a byte terminates an ANSI escape sequence
+func isAnsiTerminator(b byte) bool {
+	return (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z')
+}
+
+// cellColors is the color pair a cell is drawn in
+type cellColors struct {
+	fg, bg color.RGBA
+}
+
+// colorRun is a run of neighboring cells drawn in the same colors
+type colorRun struct {
+	text   string
+	colors cellColors
+	blank  bool // Only spaces without a background, which show no color
+}
+
+// colorRuns splits a rendered line into runs of one color pair. Spaces
+// without a background join the blank run they follow or start one.
+func colorRuns(line string) []colorRun {
+	var runs []colorRun
+	var text strings.Builder
+	flush := func(run colorRun) {
+		run.text = text.String()
+		runs = append(runs, run)
+		text.Reset()
+	}
+
+	var current colorRun
+	for i, cell := range parseANSILine(line) {
+		next := colorRun{colors: cellColors{fg: cell.fg, bg: cell.bg}, blank: cell.char == ' ' && cell.bg.A == 0}
+		if next.blank {
+			next.colors = cellColors{}
+		}
+		if i > 0 && next != current {
+			flush(current)
+		}
+		current = next
+		text.WriteRune(cell.char)
+	}
+	if text.Len() > 0 {
+		flush(current)
+	}
+	return runs
+}
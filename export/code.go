@@ -0,0 +1,291 @@
+// ABOUTME: Code generator that executes text/template templates to write source code printing the art.
+// ABOUTME: Provides per-language string escaping, the built-in language templates and user template loading.
+
+package export
+
+import (
+	"embed"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// DefaultGoIdentifier is the name of the banner constant of Go packages
+const DefaultGoIdentifier = "Banner"
+
+// GoOptions contains configuration for Go code generation
+type GoOptions struct {
+	Package    string // Generate a package of this name instead of a main program
+	Identifier string // Exported name of the banner constant (default: DefaultGoIdentifier)
+}
+
+// CodeTemplateData is the data code templates are executed with
+type CodeTemplateData struct {
+	Lines      []string // Rendered lines with their ANSI codes
+	PlainLines []string // Rendered lines with ANSI codes stripped
+	Text       string   // Text that was rendered
+	Font       string   // Name of the font, empty if unknown
+	Package    string   // Package name from GoOptions, empty for a standalone program
+	Identifier string   // Name of the banner from GoOptions (default: DefaultGoIdentifier)
+
+	NoColorFallback bool // Whether to add a plain variant for NO_COLOR, from ShellOptions
+}
+
+// newCodeTemplateData returns the template data of a result
+func newCodeTemplateData(result Result, options Options) CodeTemplateData {
+	data := CodeTemplateData{
+		Lines:      result.Lines,
+		Text:       result.Text,
+		Package:    options.Go.Package,
+		Identifier: options.Go.Identifier,
+
+		NoColorFallback: options.Shell.NoColorFallback,
+	}
+	if data.Identifier == "" {
+		data.Identifier = DefaultGoIdentifier
+	}
+	for _, line := range result.Lines {
+		data.PlainLines = append(data.PlainLines, stripANSI(line))
+	}
+	if result.Font != nil {
+		data.Font = result.Font.Name
+	}
+	return data
+}
+
+// codeFormat is a text format generated from a built-in template
+type codeFormat struct {
+	name        string
+	extension   string
+	description string
+	template    string                      // File name of the template in templates/
+	validate    func(options Options) error // Checks the options before generating, if set
+}
+
+// exporter returns the exporter of the format's built-in template
+func (f codeFormat) exporter() Exporter {
+	tmpl := builtinCodeTemplate(f.template)
+	return NewExporter(f.name, f.extension, f.description, false, func(result Result, options Options) ([]byte, error) {
+		if f.validate != nil {
+			if err := f.validate(options); err != nil {
+				return nil, err
+			}
+		}
+		code, err := GenerateCode(tmpl, newCodeTemplateData(result, options))
+		return []byte(code), err
+	})
+}
+
+// codeLanguages are the languages with built-in templates, in the order they
+// are listed in after the original code formats
+var codeLanguages = []codeFormat{
+	{"GO", ".go", "Go source code", "go.tmpl", validateGoOptions},
+	{"JS", ".js", "JavaScript source code", "js.tmpl", nil},
+	{"PY", ".py", "Python source code", "py.tmpl", nil},
+	{"RS", ".rs", "Rust source code", "rs.tmpl", nil},
+	{"SH", ".sh", "Bash script", "sh.tmpl", nil},
+	{"C", ".c", "C source code", "c.tmpl", nil},
+	{"CPP", ".cpp", "C++ source code", "cpp.tmpl", nil},
+	{"CS", ".cs", "C# source code", "cs.tmpl", nil},
+	{"JAVA", ".java", "Java source code", "java.tmpl", nil},
+	{"KT", ".kt", "Kotlin source code", "kt.tmpl", nil},
+	{"SWIFT", ".swift", "Swift source code", "swift.tmpl", nil},
+	{"RB", ".rb", "Ruby script", "rb.tmpl", nil},
+	{"PHP", ".php", "PHP script", "php.tmpl", nil},
+	{"LUA", ".lua", "Lua script", "lua.tmpl", nil},
+	{"PS1", ".ps1", "PowerShell script (PowerShell 7)", "ps1.tmpl", nil},
+	{"ZIG", ".zig", "Zig source code", "zig.tmpl", nil},
+}
+
+// validateGoOptions checks that a Go package export gets a package name and
+// an exported identifier that compile
+func validateGoOptions(options Options) error {
+	if options.Go.Package == "" {
+		return nil
+	}
+	if !token.IsIdentifier(options.Go.Package) || options.Go.Package == "_" || options.Go.Package == "main" {
+		return fmt.Errorf("invalid Go package name %q", options.Go.Package)
+	}
+	if identifier := options.Go.Identifier; identifier != "" && (!token.IsIdentifier(identifier) || !token.IsExported(identifier)) {
+		return fmt.Errorf("invalid Go identifier %q, it must be an exported name like %s", identifier, DefaultGoIdentifier)
+	}
+	return nil
+}
+
+// stringSyntax describes the string literals of a language
+type stringSyntax struct {
+	open, close string          // Quotes around the literal
+	escapes     map[rune]string // Characters written as escape sequences
+	control     string          // Format of other control characters, given their code
+}
+
+// quote returns text as a string literal. Characters outside ASCII are kept
+// as they are, since every language reads UTF-8 source.
+func (s stringSyntax) quote(text string) string {
+	var b strings.Builder
+	b.WriteString(s.open)
+	for _, r := range text {
+		if escape, ok := s.escapes[r]; ok {
+			b.WriteString(escape)
+		} else if r < 0x20 || r == 0x7f {
+			fmt.Fprintf(&b, s.control, r)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	b.WriteString(s.close)
+	return b.String()
+}
+
+// backslashEscapes returns the escapes of a double-quoted literal with
+// backslash escapes, plus any extra ones
+func backslashEscapes(extra map[rune]string) map[rune]string {
+	escapes := map[rune]string{'\\': `\\`, '"': `\"`, '\n': `\n`, '\r': `\r`, '\t': `\t`}
+	for r, escape := range extra {
+		escapes[r] = escape
+	}
+	return escapes
+}
+
+// String literal syntaxes of the built-in languages. ESC is written with a
+// fixed-length escape wherever the language has a variable-length one, so
+// the character after it cannot be read as part of the escape.
+var (
+	hexStrings        = stringSyntax{`"`, `"`, backslashEscapes(nil), `\x%02x`}                       // Go, JavaScript, Python, Rust and Zig
+	cStrings          = stringSyntax{`"`, `"`, backslashEscapes(map[rune]string{'?': `\?`}), `\%03o`} // \? avoids trigraphs
+	javaStrings       = stringSyntax{`"`, `"`, backslashEscapes(nil), `\%03o`}
+	csStrings         = stringSyntax{`"`, `"`, backslashEscapes(nil), `\u%04X`}
+	kotlinStrings     = stringSyntax{`"`, `"`, backslashEscapes(map[rune]string{'$': `\$`}), `\u%04X`}
+	swiftStrings      = stringSyntax{`"`, `"`, backslashEscapes(nil), `\u{%X}`}
+	rubyStrings       = stringSyntax{`"`, `"`, backslashEscapes(map[rune]string{'#': `\#`, 0x1b: `\e`}), `\x%02X`}
+	phpStrings        = stringSyntax{`"`, `"`, backslashEscapes(map[rune]string{'$': `\$`, 0x1b: `\e`}), `\x%02X`}
+	luaStrings        = stringSyntax{`"`, `"`, backslashEscapes(nil), `\%03d`}
+	shellStrings      = stringSyntax{`$'`, `'`, map[rune]string{'\\': `\\`, '\'': `\'`, '\n': `\n`, '\r': `\r`, '\t': `\t`}, `\x%02x`}
+	powerShellStrings = stringSyntax{`"`, `"`, map[rune]string{
+		'`': "``", '"': "`\"", '$': "`$", 0x1b: "`e", '\n': "`n", '\r': "`r", '\t': "`t",
+		// PowerShell also ends double-quoted strings at typographic quotes
+		'“': "`“", '”': "`”", '„': "`„",
+	}, `$([char]%d)`}
+)
+
+// codeTemplateFuncs are the functions available to code templates
+var codeTemplateFuncs = template.FuncMap{
+	"quoteGo":         hexStrings.quote,
+	"quoteJS":         hexStrings.quote,
+	"quotePython":     hexStrings.quote,
+	"quoteRust":       hexStrings.quote,
+	"quoteZig":        hexStrings.quote,
+	"quoteC":          cStrings.quote,
+	"quoteJava":       javaStrings.quote,
+	"quoteCSharp":     csStrings.quote,
+	"quoteKotlin":     kotlinStrings.quote,
+	"quoteSwift":      swiftStrings.quote,
+	"quoteRuby":       rubyStrings.quote,
+	"quotePHP":        phpStrings.quote,
+	"quoteLua":        luaStrings.quote,
+	"quoteShell":      shellStrings.quote,
+	"quotePowerShell": powerShellStrings.quote,
+	"quotePrintf":     printfStrings.quote,
+	"quoteFish":       fishPrintfStrings.quote,
+	"zshPrompt":       zshPrompt,
+	"bashPrompt":      bashPrompt,
+	"stripANSI":       stripANSI,
+}
+
+// codeTemplates holds the built-in templates, named after their files
+var codeTemplates = template.Must(template.New("").Funcs(codeTemplateFuncs).ParseFS(templateFS, "templates/*.tmpl"))
+
+// ParseCodeTemplate parses a code template, which can use the quote
+// functions of every built-in language (quoteGo, quoteC, quoteShell, ...)
+// and stripANSI, and is executed with CodeTemplateData
+func ParseCodeTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(codeTemplateFuncs).Parse(text)
+}
+
+// GenerateCode executes a code template with the data of a rendering
+func GenerateCode(tmpl *template.Template, data CodeTemplateData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to execute template %s: %w", tmpl.Name(), err)
+	}
+	return b.String(), nil
+}
+
+// builtinCodeTemplate returns the built-in template with the given file name
+func builtinCodeTemplate(name string) *template.Template {
+	return codeTemplates.Lookup(name)
+}
+
+// generateBuiltinCode executes a built-in template with lines
+func generateBuiltinCode(name string, lines []string) string {
+	code, _ := GenerateCode(builtinCodeTemplate(name), newCodeTemplateData(Result{Lines: lines}, Options{}))
+	return code
+}
+
+// NewTemplateExporter returns a text exporter of the given format that
+// generates files with a code template
+func NewTemplateExporter(name, extension, description string, tmpl *template.Template) Exporter {
+	return NewExporter(name, extension, description, false, func(result Result, options Options) ([]byte, error) {
+		code, err := GenerateCode(tmpl, newCodeTemplateData(result, options))
+		return []byte(code), err
+	})
+}
+
+// LoadTemplateFile parses a template file into an exporter. A file named
+// EXT.tmpl exports a format named EXT in capitals with extension .EXT.
+func LoadTemplateFile(path string) (Exporter, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := ParseCodeTemplate(filepath.Base(path), string(text))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	ext := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if ext == "" {
+		return nil, fmt.Errorf("template %s has no name", path)
+	}
+	return NewTemplateExporter(strings.ToUpper(ext), "."+ext, "Template "+path, tmpl), nil
+}
+
+// DefaultTemplateDir returns the directory user templates are loaded from,
+// bit/templates in the user's configuration directory
+func DefaultTemplateDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bit", "templates"), nil
+}
+
+// LoadTemplateDir registers an exporter for every .tmpl file in dir, in
+// name order. A template named after a built-in format, like go.tmpl,
+// replaces that format. A missing directory loads nothing. Templates that
+// cannot be read or parsed are skipped and reported in a *Warning, after the
+// others are registered.
+func LoadTemplateDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return err
+	}
+	var skipped []string
+	for _, path := range paths {
+		exporter, err := LoadTemplateFile(path)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		Register(exporter)
+	}
+	if len(skipped) > 0 {
+		return &Warning{Message: "skipped templates: " + strings.Join(skipped, "; ")}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// ABOUTME: Tests for template-driven code generation from ANSI-colored text output.
+// ABOUTME: Verifies per-language escaping, the built-in templates and loading user templates.
+
+package export
+
+import (
+	"errors"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestQuote_Languages(t *testing.T) {
+	text := "\x1b[31m\"\\$#`'?\x1b[0m█"
+	tests := []struct {
+		syntax   stringSyntax
+		expected string
+	}{
+		{hexStrings, `"\x1b[31m\"\\$#` + "`'?" + `\x1b[0m█"`},
+		{cStrings, `"\033[31m\"\\$#` + "`'\\?" + `\033[0m█"`},
+		{csStrings, `"\u001B[31m\"\\$#` + "`'?" + `\u001B[0m█"`},
+		{kotlinStrings, `"\u001B[31m\"\\\$#` + "`'?" + `\u001B[0m█"`},
+		{swiftStrings, `"\u{1B}[31m\"\\$#` + "`'?" + `\u{1B}[0m█"`},
+		{rubyStrings, `"\e[31m\"\\$\#` + "`'?" + `\e[0m█"`},
+		{phpStrings, `"\e[31m\"\\\$#` + "`'?" + `\e[0m█"`},
+		{luaStrings, `"\027[31m\"\\$#` + "`'?" + `\027[0m█"`},
+		{shellStrings, `$'\x1b[31m"\\$#` + "`\\'?" + `\x1b[0m█'`},
+		{powerShellStrings, "\"`e[31m`\"\\`$#``'?`e[0m█\""},
+	}
+	for _, test := range tests {
+		if got := test.syntax.quote(text); got != test.expected {
+			t.Errorf("expected %s, got %s", test.expected, got)
+		}
+	}
+}
+
+func TestGenerateGoCode_ValidSource(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m█\\\"\x1b[0m", "plain"}
+	code := GenerateGoCode(lines)
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "art.go", code, 0); err != nil {
+		t.Fatalf("generated Go does not parse: %v\n%s", err, code)
+	}
+	for _, line := range lines {
+		if !strings.Contains(code, strconv.Quote(line)) {
+			t.Errorf("expected the line as %s in:\n%s", strconv.Quote(line), code)
+		}
+	}
+}
+
+func TestGoExport_Package(t *testing.T) {
+	options := DefaultOptions()
+	options.Go = GoOptions{Package: "banner", Identifier: "Logo"}
+	code, err := Lookup("GO").Export(Result{Lines: []string{"\x1b[31m█\x1b[0m", "▀"}}, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	formatted, err := format.Source(code)
+	if err != nil {
+		t.Fatalf("generated package does not parse: %v\n%s", err, code)
+	}
+	if string(formatted) != string(code) {
+		t.Errorf("expected gofmt-formatted code, got:\n%s", code)
+	}
+	for _, expected := range []string{
+		"package banner\n",
+		"const Logo = \"\" +\n\t\"\\x1b[31m█\\x1b[0m\\n\" +\n\t\"▀\\n\"\n",
+		"const LogoPlain = \"\" +\n\t\"█\\n\" +\n\t\"▀\\n\"\n",
+		"func Print(w io.Writer) error {",
+		`os.Getenv("NO_COLOR")`,
+	} {
+		if !strings.Contains(string(code), expected) {
+			t.Errorf("expected %q in:\n%s", expected, code)
+		}
+	}
+}
+
+func TestGoExport_InvalidNames(t *testing.T) {
+	for _, goOptions := range []GoOptions{
+		{Package: "main"},
+		{Package: "my-banner"},
+		{Package: "banner", Identifier: "banner"},
+		{Package: "banner", Identifier: "2Banner"},
+	} {
+		options := DefaultOptions()
+		options.Go = goOptions
+		if _, err := Lookup("GO").Export(Result{Lines: []string{"█"}}, options); err == nil {
+			t.Errorf("expected an error for %+v, got nil", goOptions)
+		}
+	}
+}
+
+func TestBuiltinCodeTemplates(t *testing.T) {
+	result := Result{Lines: []string{"\x1b[31m█\x1b[0m"}}
+	for _, language := range codeLanguages {
+		name := language.name
+		exporter := Lookup(name)
+		if exporter == nil {
+			t.Errorf("expected a %s exporter", name)
+			continue
+		}
+		code, err := exporter.Export(result, DefaultOptions())
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		if !strings.Contains(string(code), "█") || strings.Contains(string(code), "\x1b") {
+			t.Errorf("%s: expected the line with ESC escaped, got:\n%s", name, code)
+		}
+	}
+}
+
+func TestLoadTemplateDir(t *testing.T) {
+	dir := t.TempDir()
+	template := "{{range .PlainLines}}{{quoteC .}}\n{{end}}{{.Text}} in {{.Font}}\n"
+	if err := os.WriteFile(filepath.Join(dir, "art.tmpl"), []byte(template), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadTemplateDir(dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	exporter := LookupExtension(".art")
+	if exporter == nil || exporter.Name() != "ART" || exporter.IsBinary() {
+		t.Fatalf("expected a text ART exporter, got %v", exporter)
+	}
+	code, err := exporter.Export(Result{Lines: []string{"\x1b[31m\"A\"\x1b[0m"}, Text: "A"}, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "\"\\\"A\\\"\"\nA in \n"; string(code) != expected {
+		t.Errorf("expected %q, got %q", expected, code)
+	}
+}
+
+func TestLoadTemplateDir_SkipsBrokenTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte("{{range}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "good.tmpl"), []byte("{{.Text}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := LoadTemplateDir(dir)
+	var warning *Warning
+	if !errors.As(err, &warning) || !strings.Contains(warning.Message, "broken.tmpl") {
+		t.Fatalf("expected a warning naming broken.tmpl, got %v", err)
+	}
+	if LookupExtension(".broken") != nil {
+		t.Error("expected the broken template not to be registered")
+	}
+	if exporter := LookupExtension(".good"); exporter == nil {
+		t.Error("expected the template after the broken one to be registered")
+	}
+	if Lookup("GO") == nil {
+		t.Error("expected the built-in formats to stay registered")
+	}
+}
+
+func TestLoadTemplateDir_MissingDirectory(t *testing.T) {
+	if err := LoadTemplateDir(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Errorf("expected a missing directory to load nothing, got %v", err)
+	}
+}
+
+func TestLoadTemplateFile_ParseError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.tmpl")
+	if err := os.WriteFile(path, []byte("{{range}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTemplateFile(path); err == nil {
+		t.Error("expected a parse error, got nil")
+	}
+}
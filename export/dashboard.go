@@ -0,0 +1,242 @@
+// ABOUTME: Editor and terminal start screen generators: Neovim headers, Emacs banners and tmux status lines.
+// ABOUTME: Redraws the cell colors of rendered lines as highlight groups, face properties or tmux styles.
+
+package export
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// NvimHighlightPrefix is the prefix of the highlight groups of Neovim headers
+const NvimHighlightPrefix = "BitHeader"
+
+// TmuxMaxBannerLines is the number of banner lines a tmux status fits below
+// the window list, as tmux draws at most 5 status lines. Taller art is folded
+// into half blocks, two rows per line, and cut off if it is still too tall.
+const TmuxMaxBannerLines = 4
+
+// elispStrings is the string literal syntax of Emacs Lisp
+var elispStrings = stringSyntax{`"`, `"`, backslashEscapes(nil), `\%03o`}
+
+// GenerateNvimHeader creates a Lua module for alpha-nvim and dashboard-nvim
+// from rendered ANSI lines: the header lines as val, one highlight group per
+// color pair, and alpha-nvim's per-line highlights as opts.hl.
+// Returns the Lua source or error.
+func GenerateNvimHeader(lines []string) (string, error) {
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no content to export")
+	}
+
+	// Groups are numbered in the order their colors first appear
+	var groupColors []cellColors
+	groups := make(map[cellColors]string)
+
+	var val, hl strings.Builder
+	for _, line := range lines {
+		var text strings.Builder
+		var lineHL []string
+		for _, run := range colorRuns(line) {
+			start := text.Len()
+			text.WriteString(run.text)
+			if run.blank {
+				continue
+			}
+			group, ok := groups[run.colors]
+			if !ok {
+				group = fmt.Sprintf("%s%d", NvimHighlightPrefix, len(groupColors)+1)
+				groups[run.colors] = group
+				groupColors = append(groupColors, run.colors)
+			}
+			lineHL = append(lineHL, fmt.Sprintf("{ %q, %d, %d }", group, start, text.Len()))
+		}
+		fmt.Fprintf(&val, "  %s,\n", luaStrings.quote(text.String()))
+		fmt.Fprintf(&hl, "    { %s },\n", strings.Join(lineHL, ", "))
+	}
+
+	var b strings.Builder
+	b.WriteString("-- Generated Neovim dashboard header\n")
+	b.WriteString("--\n")
+	b.WriteString("-- alpha-nvim:\n")
+	b.WriteString("--   local header = require(\"header\")\n")
+	b.WriteString("--   dashboard.section.header.val = header.val\n")
+	b.WriteString("--   dashboard.section.header.opts.hl = header.opts.hl\n")
+	b.WriteString("-- dashboard-nvim, which colors the whole header with DashboardHeader:\n")
+	b.WriteString("--   config = { header = require(\"header\").val }\n")
+	b.WriteString("local M = {}\n\n")
+	b.WriteString("M.val = {\n")
+	b.WriteString(val.String())
+	b.WriteString("}\n\n")
+
+	b.WriteString("-- Highlight groups of the header colors, set again when the colorscheme changes\n")
+	b.WriteString("local highlights = {\n")
+	for _, colors := range groupColors {
+		attributes := fmt.Sprintf("fg = %q", hexColor(colors.fg))
+		if colors.bg.A != 0 {
+			attributes += fmt.Sprintf(", bg = %q", hexColor(colors.bg))
+		}
+		fmt.Fprintf(&b, "  { %q, { %s } },\n", groups[colors], attributes)
+	}
+	b.WriteString("}\n\n")
+	b.WriteString("local function set_highlights()\n")
+	b.WriteString("  for _, highlight in ipairs(highlights) do\n")
+	b.WriteString("    vim.api.nvim_set_hl(0, highlight[1], highlight[2])\n")
+	b.WriteString("  end\n")
+	b.WriteString("end\n\n")
+	b.WriteString("set_highlights()\n")
+	b.WriteString("vim.api.nvim_create_autocmd(\"ColorScheme\", { callback = set_highlights })\n\n")
+
+	b.WriteString("-- alpha-nvim highlights: per line, { group, start byte, end byte }\n")
+	b.WriteString("M.opts = {\n")
+	b.WriteString("  position = \"center\",\n")
+	b.WriteString("  hl = {\n")
+	b.WriteString(hl.String())
+	b.WriteString("  },\n")
+	b.WriteString("}\n\n")
+	b.WriteString("return M\n")
+	return b.String(), nil
+}
+
+// GenerateEmacsBanner creates an Emacs Lisp file defining the banner as a
+// string with face properties, and a function inserting it centered that can
+// replace the banner in dashboard-startupify-list.
+// Returns the Emacs Lisp source or error.
+func GenerateEmacsBanner(lines []string) (string, error) {
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no content to export")
+	}
+
+	var b strings.Builder
+	b.WriteString(";;; bit-banner.el --- Banner generated by bit  -*- lexical-binding: t; -*-\n\n")
+	b.WriteString(";; Show it in place of the dashboard banner:\n")
+	b.WriteString(";;   (require 'bit-banner)\n")
+	b.WriteString(";;   (setq dashboard-startupify-list\n")
+	b.WriteString(";;         (cons #'bit-banner-insert\n")
+	b.WriteString(";;               (remq 'dashboard-insert-banner dashboard-startupify-list)))\n\n")
+	b.WriteString("(defconst bit-banner\n")
+	b.WriteString("  (concat\n")
+	for i, line := range lines {
+		for _, run := range colorRuns(line) {
+			if run.blank {
+				fmt.Fprintf(&b, "   %s\n", elispStrings.quote(run.text))
+				continue
+			}
+			face := fmt.Sprintf(":foreground %q", hexColor(run.colors.fg))
+			if run.colors.bg.A != 0 {
+				face += fmt.Sprintf(" :background %q", hexColor(run.colors.bg))
+			}
+			fmt.Fprintf(&b, "   (propertize %s 'face '(%s))\n", elispStrings.quote(run.text), face)
+		}
+		if i < len(lines)-1 {
+			b.WriteString("   \"\\n\"\n")
+		}
+	}
+	b.WriteString("   )\n")
+	b.WriteString("  \"The banner, colored with face properties.\")\n\n")
+	b.WriteString("(defun bit-banner-insert ()\n")
+	b.WriteString("  \"Insert `bit-banner' centered in the window.\"\n")
+	b.WriteString("  (let* ((lines (split-string bit-banner \"\\n\"))\n")
+	b.WriteString("         (width (apply #'max (mapcar #'string-width lines)))\n")
+	b.WriteString("         (margin (make-string (max 0 (/ (- (window-width) width) 2)) ?\\s)))\n")
+	b.WriteString("    (dolist (line lines)\n")
+	b.WriteString("      (insert margin line \"\\n\"))))\n\n")
+	b.WriteString("(provide 'bit-banner)\n")
+	b.WriteString(";;; bit-banner.el ends here\n")
+	return b.String(), nil
+}
+
+// tmuxText escapes text for a double-quoted tmux format: # starts a format
+// and tmux expands environment variables in double quotes
+var tmuxText = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, `#`, `##`)
+
+// GenerateTmuxStatus creates a tmux configuration snippet drawing the
+// rendered lines as extra status lines below the window list, colored with
+// #[fg=#rrggbb] styles. Art taller than TmuxMaxBannerLines is folded into
+// half blocks and, when still too tall, cut off; the snippet then comes with
+// a *Warning. Returns the snippet or error.
+func GenerateTmuxStatus(lines []string) (string, error) {
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no content to export")
+	}
+	var warning error
+	if len(lines) > TmuxMaxBannerLines {
+		original := len(lines)
+		lines = foldRows(lines)
+		if len(lines) > TmuxMaxBannerLines {
+			warning = &Warning{Message: fmt.Sprintf("tmux fits %d banner lines below the window list, so %d lines were folded into half blocks and cut to %d (try a smaller font or -scale -1)", TmuxMaxBannerLines, original, TmuxMaxBannerLines)}
+			lines = lines[:TmuxMaxBannerLines]
+		} else {
+			warning = &Warning{Message: fmt.Sprintf("tmux fits %d banner lines below the window list, so %d lines were folded into %d lines of half blocks", TmuxMaxBannerLines, original, len(lines))}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated tmux status banner: add to ~/.tmux.conf or load with source-file\n")
+	fmt.Fprintf(&b, "set -g status %d\n", len(lines)+1)
+	for i, line := range lines {
+		var format strings.Builder
+		format.WriteString("#[align=centre]")
+		for _, run := range colorRuns(line) {
+			if run.blank {
+				format.WriteString("#[default]")
+			} else if run.colors.bg.A != 0 {
+				fmt.Fprintf(&format, "#[fg=%s,bg=%s]", hexColor(run.colors.fg), hexColor(run.colors.bg))
+			} else {
+				fmt.Fprintf(&format, "#[fg=%s,bg=default]", hexColor(run.colors.fg))
+			}
+			format.WriteString(tmuxText.Replace(run.text))
+		}
+		format.WriteString("#[default]")
+		fmt.Fprintf(&b, "set -g status-format[%d] \"%s\"\n", i+1, format.String())
+	}
+	return b.String(), warning
+}
+
+// foldRows draws every pair of rendered lines as one line of half blocks,
+// with the upper cell as the foreground of ▀ and the lower one as its
+// background
+func foldRows(lines []string) []string {
+	var folded []string
+	for y := 0; y < len(lines); y += 2 {
+		upperCells := parseANSILine(lines[y])
+		var lowerCells []ansiCell
+		if y+1 < len(lines) {
+			lowerCells = parseANSILine(lines[y+1])
+		}
+
+		var b strings.Builder
+		for x := range max(len(upperCells), len(lowerCells)) {
+			upper, upperShown := foldedShade(upperCells, x)
+			lower, lowerShown := foldedShade(lowerCells, x)
+			switch {
+			case upperShown && lowerShown && upper == lower:
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm█\x1b[0m", upper.R, upper.G, upper.B)
+			case upperShown && lowerShown:
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm\x1b[48;2;%d;%d;%dm▀\x1b[0m", upper.R, upper.G, upper.B, lower.R, lower.G, lower.B)
+			case upperShown:
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm▀\x1b[0m", upper.R, upper.G, upper.B)
+			case lowerShown:
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm▄\x1b[0m", lower.R, lower.G, lower.B)
+			default:
+				b.WriteRune(' ')
+			}
+		}
+		folded = append(folded, strings.TrimRight(b.String(), " "))
+	}
+	return folded
+}
+
+// foldedShade returns the color cell x shows when folded into half a cell:
+// its foreground for a character, its background for a space, and whether it
+// shows anything at all
+func foldedShade(cells []ansiCell, x int) (color.RGBA, bool) {
+	if x >= len(cells) {
+		return color.RGBA{}, false
+	}
+	cell := cells[x]
+	if cell.char != ' ' {
+		return cell.fg, true
+	}
+	return cell.bg, cell.bg.A != 0
+}
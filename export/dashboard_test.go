@@ -0,0 +1,130 @@
+// ABOUTME: Tests for the Neovim header, Emacs banner and tmux status exports.
+// ABOUTME: Verifies color runs, highlight byte ranges, face properties and tmux escaping.
+
+package export
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestColorRuns(t *testing.T) {
+	runs := colorRuns("\x1b[38;2;255;0;0m██  \x1b[48;2;0;0;255m█ \x1b[0m \x1b[38;2;0;255;0m█")
+	expected := []colorRun{
+		{text: "██", colors: cellColors{fg: color.RGBA{R: 255, A: 255}}},
+		{text: "  ", blank: true},
+		{text: "█ ", colors: cellColors{fg: color.RGBA{R: 255, A: 255}, bg: color.RGBA{B: 255, A: 255}}},
+		{text: " ", blank: true},
+		{text: "█", colors: cellColors{fg: color.RGBA{G: 255, A: 255}}},
+	}
+	if len(runs) != len(expected) {
+		t.Fatalf("expected %d runs, got %d: %+v", len(expected), len(runs), runs)
+	}
+	for i := range expected {
+		if runs[i] != expected[i] {
+			t.Errorf("run %d: expected %+v, got %+v", i, expected[i], runs[i])
+		}
+	}
+}
+
+func TestGenerateNvimHeader(t *testing.T) {
+	lines := []string{
+		"\x1b[38;2;255;0;0m█ █\x1b[0m",
+		"\x1b[38;2;0;0;255m\"\x1b[38;2;255;0;0m█",
+	}
+	got, err := GenerateNvimHeader(lines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"  \"█ █\",\n",
+		"  \"\\\"█\",\n",
+		`{ "BitHeader1", { fg = "#ff0000" } },`,
+		`{ "BitHeader2", { fg = "#0000ff" } },`,
+		// █ is 3 bytes, so the runs of the first line are bytes 0-3 and 4-7
+		`{ { "BitHeader1", 0, 3 }, { "BitHeader1", 4, 7 } },`,
+		`{ { "BitHeader2", 0, 1 }, { "BitHeader1", 1, 4 } },`,
+		"return M\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateEmacsBanner(t *testing.T) {
+	got, err := GenerateEmacsBanner([]string{"\x1b[38;2;255;0;0m\x1b[48;2;0;0;0m\"█\x1b[0m  ", "\x1b[38;2;0;255;0m█"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`(propertize "\"█" 'face '(:foreground "#ff0000" :background "#000000"))`,
+		"   \"  \"\n   \"\\n\"\n",
+		`(propertize "█" 'face '(:foreground "#00ff00"))`,
+		"(provide 'bit-banner)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, got)
+		}
+	}
+}
+
+func TestGenerateTmuxStatus(t *testing.T) {
+	got, err := GenerateTmuxStatus([]string{"\x1b[38;2;255;0;0m#$ \x1b[48;2;0;0;255m\"\x1b[0m"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "# Generated tmux status banner: add to ~/.tmux.conf or load with source-file\n" +
+		"set -g status 2\n" +
+		`set -g status-format[1] "#[align=centre]#[fg=#ff0000,bg=default]##\$#[default] #[fg=#ff0000,bg=#0000ff]\"#[default]"` + "\n"
+	if got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestGenerateTmuxStatus_FoldsTallArt(t *testing.T) {
+	red, blue := "\x1b[38;2;255;0;0m█\x1b[0m", "\x1b[38;2;0;0;255m█\x1b[0m"
+	lines := []string{red + red + " ", red + blue, "  " + red, red, red + red}
+
+	got, err := GenerateTmuxStatus(lines)
+	var warning *Warning
+	if !errors.As(err, &warning) || !strings.Contains(warning.Message, "5 lines were folded into 3") {
+		t.Fatalf("expected a folding warning, got %v", err)
+	}
+	// Each status line holds two rows: ▀ in the upper color over the lower one
+	expected := "# Generated tmux status banner: add to ~/.tmux.conf or load with source-file\n" +
+		"set -g status 4\n" +
+		`set -g status-format[1] "#[align=centre]#[fg=#ff0000,bg=default]█#[fg=#ff0000,bg=#0000ff]▀#[default]"` + "\n" +
+		`set -g status-format[2] "#[align=centre]#[fg=#ff0000,bg=default]▄#[default] #[fg=#ff0000,bg=default]▀#[default]"` + "\n" +
+		`set -g status-format[3] "#[align=centre]#[fg=#ff0000,bg=default]▀▀#[default]"` + "\n"
+	if got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestGenerateTmuxStatus_CutsArtTallerThanTwiceTheLines(t *testing.T) {
+	lines := make([]string, 2*TmuxMaxBannerLines+3)
+	for i := range lines {
+		lines[i] = "█"
+	}
+	got, err := GenerateTmuxStatus(lines)
+	var warning *Warning
+	if !errors.As(err, &warning) || !strings.Contains(warning.Message, "cut to 4") {
+		t.Fatalf("expected a warning about cut lines, got %v", err)
+	}
+	if !strings.Contains(got, fmt.Sprintf("set -g status %d\n", TmuxMaxBannerLines+1)) ||
+		strings.Contains(got, fmt.Sprintf("status-format[%d]", TmuxMaxBannerLines+1)) {
+		t.Errorf("expected %d banner lines, got:\n%s", TmuxMaxBannerLines, got)
+	}
+}
+
+func TestDashboardExports_EmptyInput(t *testing.T) {
+	for _, name := range []string{"NVIM", "EMACS", "TMUX"} {
+		if _, err := Lookup(name).Export(Result{}, DefaultOptions()); err == nil {
+			t.Errorf("%s: expected an error for empty input", name)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+// ABOUTME: Exporter interface and the registry the TUI and CLI enumerate formats from.
+// ABOUTME: Registers the built-in formats; library users can register exporters of their own.
+
+package export
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/paulilaaso/bit/ansifonts"
+)
+
+// Exporter turns a rendering into the contents of a file in one format
+type Exporter interface {
+	Name() string        // Canonical key (e.g., "TXT", "PNG")
+	Extension() string   // File extension including the dot (e.g., ".txt")
+	Description() string // Short description shown in format lists
+	IsBinary() bool      // True when the output is not text
+	Animated() bool      // True when the format plays Result.Frames, which take a while to render
+	Export(result Result, options Options) ([]byte, error)
+}
+
+// Warning is returned together with a usable result when something had to be
+// left out or changed, such as a user template that does not parse or lines an
+// exporter had to fold to fit its format. Callers report it and keep the result.
+type Warning struct {
+	Message string
+}
+
+func (w *Warning) Error() string { return w.Message }
+
+// Result is a rendering handed to exporters
+type Result struct {
+	Lines  []string   // Rendered ANSI lines
+	Frames [][]string // Frames of an animation; animated formats use Lines as the only frame when empty
+	Text   string     // Text that was rendered, without markup
+	Font   *ansifonts.Font
+}
+
+// animationFrames returns the frames of the result, or its lines as one frame
+func (r Result) animationFrames() [][]string {
+	if len(r.Frames) > 0 {
+		return r.Frames
+	}
+	return [][]string{r.Lines}
+}
+
+// Options holds the configuration of each built-in format. Exporters read
+// the part they need and ignore the rest.
+type Options struct {
+	PNG   PNGOptions
+	GIF   GIFOptions
+	SVG   SVGOptions
+	HTML  HTMLOptions
+	ANS   ANSOptions
+	Go    GoOptions
+	Shell ShellOptions
+}
+
+// DefaultOptions returns the default options of every built-in format, with
+// PNG cells in the terminal aspect ratio
+func DefaultOptions() Options {
+	return Options{
+		PNG:  TerminalAspectRatioPNGOptions(),
+		GIF:  DefaultGIFOptions(),
+		SVG:  DefaultSVGOptions(),
+		HTML: DefaultHTMLOptions(),
+		ANS:  DefaultANSOptions(),
+	}
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   []Exporter
+)
+
+// Register adds an exporter to the registry. An exporter with the same name
+// replaces the registered one in its place; others are appended.
+func Register(exporter Exporter) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for i, registered := range registry {
+		if strings.EqualFold(registered.Name(), exporter.Name()) {
+			registry[i] = exporter
+			return
+		}
+	}
+	registry = append(registry, exporter)
+}
+
+// Exporters returns the registered exporters in registration order
+func Exporters() []Exporter {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]Exporter(nil), registry...)
+}
+
+// Lookup returns the exporter with the given name, ignoring case, or nil
+func Lookup(name string) Exporter {
+	for _, exporter := range Exporters() {
+		if strings.EqualFold(exporter.Name(), name) {
+			return exporter
+		}
+	}
+	return nil
+}
+
+// LookupExtension returns the first exporter writing files with the given
+// extension, with or without the dot and ignoring case, or nil. Formats
+// without an extension are only found by name.
+func LookupExtension(ext string) Exporter {
+	if strings.TrimPrefix(ext, ".") == "" {
+		return nil
+	}
+	ext = "." + strings.TrimPrefix(strings.ToLower(ext), ".")
+	for _, exporter := range Exporters() {
+		if strings.EqualFold(exporter.Extension(), ext) {
+			return exporter
+		}
+	}
+	return nil
+}
+
+// LookupFilename returns the exporter whose extension ends the file name of
+// path, ignoring case, or nil. The longest extension wins, so header.nvim.lua
+// finds the Neovim header rather than the Lua script.
+func LookupFilename(path string) Exporter {
+	name := strings.ToLower(filepath.Base(path))
+	var best Exporter
+	for _, exporter := range Exporters() {
+		ext := strings.ToLower(exporter.Extension())
+		if ext == "" || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		if best == nil || len(ext) > len(best.Extension()) {
+			best = exporter
+		}
+	}
+	return best
+}
+
+// funcExporter is an Exporter backed by a generate function
+type funcExporter struct {
+	name        string
+	extension   string
+	description string
+	binary      bool
+	animated    bool
+	generate    func(result Result, options Options) ([]byte, error)
+}
+
+// NewExporter returns an exporter of the given format that generates files
+// with generate, for registering formats without declaring a type
+func NewExporter(name, extension, description string, binary bool, generate func(result Result, options Options) ([]byte, error)) Exporter {
+	return funcExporter{name: name, extension: extension, description: description, binary: binary, generate: generate}
+}
+
+// NewAnimatedExporter is NewExporter for a format that plays the frames of
+// the result
+func NewAnimatedExporter(name, extension, description string, binary bool, generate func(result Result, options Options) ([]byte, error)) Exporter {
+	return funcExporter{name: name, extension: extension, description: description, binary: binary, animated: true, generate: generate}
+}
+
+func (e funcExporter) Name() string        { return e.name }
+func (e funcExporter) Extension() string   { return e.extension }
+func (e funcExporter) Description() string { return e.description }
+func (e funcExporter) IsBinary() bool      { return e.binary }
+func (e funcExporter) Animated() bool      { return e.animated }
+
+func (e funcExporter) Export(result Result, options Options) ([]byte, error) {
+	return e.generate(result, options)
+}
+
+func init() {
+	Register(NewExporter("PNG", ".png", "PNG image (transparent background)", true, func(result Result, options Options) ([]byte, error) {
+		return GeneratePNG(result.Lines, options.PNG)
+	}))
+	Register(NewAnimatedExporter("GIF", ".gif", "Animated GIF image (transparent background)", true, func(result Result, options Options) ([]byte, error) {
+		return GenerateGIF(result.animationFrames(), options.GIF)
+	}))
+	Register(NewExporter("SVG", ".svg", "SVG vector image (transparent background)", false, func(result Result, options Options) ([]byte, error) {
+		content, err := GenerateSVG(result.Lines, options.SVG)
+		return []byte(content), err
+	}))
+	Register(NewExporter("HTML", ".html", "HTML page with colored text", false, func(result Result, options Options) ([]byte, error) {
+		content, err := GenerateHTML(result.Lines, options.HTML)
+		return []byte(content), err
+	}))
+	Register(NewExporter("ANS", ".ans", "ANSI art with SAUCE metadata", true, func(result Result, options Options) ([]byte, error) {
+		return GenerateANS(result.Lines, ansOptionsFor(result, options.ANS))
+	}))
+	Register(NewExporter("TXT", ".txt", "Plain text file", false, func(result Result, _ Options) ([]byte, error) {
+		return []byte(GenerateTXTCode(result.Lines)), nil
+	}))
+	for _, language := range codeLanguages {
+		Register(language.exporter())
+	}
+	for _, format := range shellFormats {
+		Register(format.exporter())
+	}
+	Register(NewExporter("NVIM", ".nvim.lua", "Neovim alpha/dashboard header", false, func(result Result, _ Options) ([]byte, error) {
+		content, err := GenerateNvimHeader(result.Lines)
+		return []byte(content), err
+	}))
+	Register(NewExporter("EMACS", ".el", "Emacs dashboard banner", false, func(result Result, _ Options) ([]byte, error) {
+		content, err := GenerateEmacsBanner(result.Lines)
+		return []byte(content), err
+	}))
+	Register(NewExporter("TMUX", ".tmux", "tmux status line snippet", false, func(result Result, _ Options) ([]byte, error) {
+		content, err := GenerateTmuxStatus(result.Lines)
+		return []byte(content), err
+	}))
+}
+
+// ansOptionsFor fills the SAUCE metadata left empty in options from the
+// result: the first line of the text as title, and the font and its author
+func ansOptionsFor(result Result, options ANSOptions) ANSOptions {
+	if options.Title == "" {
+		options.Title, _, _ = strings.Cut(result.Text, "\n")
+	}
+	if result.Font != nil {
+		if options.Author == "" {
+			options.Author = result.Font.FontData.Author
+		}
+		if options.FontName == "" {
+			options.FontName = result.Font.Name
+		}
+	}
+	return options
+}
@@ -0,0 +1,162 @@
+// ABOUTME: Tests for the exporter registry and the built-in exporters.
+// ABOUTME: Verifies lookup, registration order, replacement and that built-ins match the generators.
+
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestExporters_BuiltinOrder(t *testing.T) {
+	expected := []string{"PNG", "GIF", "SVG", "HTML", "ANS", "TXT", "GO", "JS", "PY", "RS", "SH",
+		"C", "CPP", "CS", "JAVA", "KT", "SWIFT", "RB", "PHP", "LUA", "PS1", "ZIG",
+		"MOTD", "UPDATEMOTD", "ZSH", "BASH", "FISH", "PSPROFILE", "NVIM", "EMACS", "TMUX"}
+	exporters := Exporters()
+	if len(exporters) < len(expected) {
+		t.Fatalf("expected at least %d exporters, got %d", len(expected), len(exporters))
+	}
+	for i, name := range expected {
+		if exporters[i].Name() != name {
+			t.Errorf("exporter %d: expected %s, got %s", i, name, exporters[i].Name())
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	if exporter := Lookup("png"); exporter == nil || exporter.Name() != "PNG" {
+		t.Errorf("expected the PNG exporter for \"png\", got %v", exporter)
+	}
+	if exporter := LookupExtension("HTML"); exporter == nil || exporter.Name() != "HTML" {
+		t.Errorf("expected the HTML exporter for the \"HTML\" extension, got %v", exporter)
+	}
+	if exporter := Lookup("nope"); exporter != nil {
+		t.Errorf("expected no exporter, got %s", exporter.Name())
+	}
+}
+
+func TestRegister_CustomExporter(t *testing.T) {
+	exporter := NewExporter("TEST", ".test", "Test format", false, func(result Result, _ Options) ([]byte, error) {
+		return []byte(stripANSI(result.Lines[0])), nil
+	})
+	Register(exporter)
+
+	// The export manager lists registered exporters
+	if format := NewExportManager().GetFormatByName("TEST"); format == nil || format.Extension != ".test" {
+		t.Fatalf("expected the export manager to list the TEST format, got %v", format)
+	}
+
+	// Registering the same name again replaces the exporter in its place
+	count := len(Exporters())
+	Register(NewExporter("test", ".tst", "Replacement", false, exporter.Export))
+	if len(Exporters()) != count {
+		t.Errorf("expected %d exporters after replacing, got %d", count, len(Exporters()))
+	}
+	if got := Lookup("TEST"); got == nil || got.Extension() != ".tst" {
+		t.Errorf("expected the replacement exporter, got %v", got)
+	}
+
+	data, err := Lookup("TEST").Export(Result{Lines: []string{"\x1b[38;2;255;0;0mhi\x1b[0m"}}, DefaultOptions())
+	if err != nil || string(data) != "hi" {
+		t.Errorf("expected \"hi\", got %q (%v)", data, err)
+	}
+}
+
+func TestBuiltinExporters_MatchGenerators(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m█▀\x1b[0m"}
+	result := Result{Lines: lines, Text: "Hi\nthere"}
+	options := DefaultOptions()
+
+	png, _ := GeneratePNG(lines, options.PNG)
+	svg, _ := GenerateSVG(lines, options.SVG)
+	expected := map[string][]byte{
+		"PNG": png,
+		"SVG": []byte(svg),
+		"TXT": []byte(GenerateTXTCode(lines)),
+		"GO":  []byte(GenerateGoCode(lines)),
+	}
+	for name, want := range expected {
+		data, err := Lookup(name).Export(result, options)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if !bytes.Equal(data, want) {
+			t.Errorf("%s: expected the output of its generator", name)
+		}
+	}
+
+	// GIF exports use the lines as the only frame without frames
+	data, err := Lookup("GIF").Export(result, options)
+	if err != nil {
+		t.Fatalf("GIF: unexpected error: %v", err)
+	}
+	if frames := len(decodeGIF(t, data).Image); frames != 1 {
+		t.Errorf("expected 1 frame, got %d", frames)
+	}
+
+	// ANS exports take the SAUCE title from the first line of the text
+	data, err = Lookup("ANS").Export(result, options)
+	if err != nil {
+		t.Fatalf("ANS: unexpected error: %v", err)
+	}
+	if title := string(bytes.TrimRight(sauceRecord(t, data)[7:42], " ")); title != "Hi" {
+		t.Errorf("expected the title \"Hi\", got %q", title)
+	}
+}
+
+func TestExporters_Animated(t *testing.T) {
+	// GIF is the only built-in format that plays animation frames
+	for _, exporter := range Exporters() {
+		if exporter.Animated() != (exporter.Name() == "GIF") {
+			t.Errorf("%s: expected Animated() to be %v", exporter.Name(), !exporter.Animated())
+		}
+	}
+
+	exporter := NewAnimatedExporter("FRAMES", ".frames", "Frame count", false, func(result Result, _ Options) ([]byte, error) {
+		return []byte{byte(len(result.animationFrames()))}, nil
+	})
+	if !exporter.Animated() || exporter.IsBinary() {
+		t.Errorf("expected an animated text exporter, got animated %v binary %v", exporter.Animated(), exporter.IsBinary())
+	}
+}
+
+func TestLookupFilename(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string // Exporter name, empty for none
+	}{
+		{"banner.lua", "LUA"},
+		{"lua/header.nvim.lua", "NVIM"},
+		{"HEADER.NVIM.LUA", "NVIM"},
+		{"banner.ps1", "PS1"},
+		{"Microsoft.PowerShell_profile.profile.ps1", "PSPROFILE"},
+		{"status.tmux", "TMUX"},
+		{"art.unknown", ""},
+		{"noextension", ""},
+	}
+	for _, tt := range tests {
+		exporter := LookupFilename(tt.path)
+		switch {
+		case tt.expected == "" && exporter != nil:
+			t.Errorf("%s: expected no exporter, got %s", tt.path, exporter.Name())
+		case tt.expected != "" && (exporter == nil || exporter.Name() != tt.expected):
+			t.Errorf("%s: expected %s, got %v", tt.path, tt.expected, exporter)
+		}
+	}
+}
+
+func TestExporters_DistinctExtensions(t *testing.T) {
+	// Every file name maps to one format
+	seen := map[string]string{}
+	for _, exporter := range Exporters() {
+		ext := strings.ToLower(exporter.Extension())
+		if ext == "" {
+			continue
+		}
+		if other, ok := seen[ext]; ok {
+			t.Errorf("%s and %s share the extension %s", other, exporter.Name(), ext)
+		}
+		seen[ext] = exporter.Name()
+	}
+}
@@ -0,0 +1,180 @@
+// ABOUTME: GIF generator that turns a sequence of rendered frames into an animated GIF.
+// ABOUTME: Frames are drawn with the PNG cell renderer and quantized to one shared palette.
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"sort"
+)
+
+// DefaultGIFDelay is the delay between frames in hundredths of a second used
+// when Delay is zero
+const DefaultGIFDelay = 10
+
+// gifMaxColors is the number of palette entries left after the transparent one
+const gifMaxColors = 255
+
+// GIFOptions contains configuration for GIF generation
+type GIFOptions struct {
+	CellWidth  int   // Pixels per character cell width (default: 8)
+	CellHeight int   // Pixels per character cell height (default: 16)
+	Delay      int   // Delay between frames in hundredths of a second (default: DefaultGIFDelay)
+	Delays     []int // Per-frame delays overriding Delay where positive
+	LoopCount  int   // As in image/gif: 0 loops forever, -1 plays once, n plays n+1 times
+
+	// FillChars maps custom fill characters back to the block or shade
+	// character they replace, as in PNGOptions
+	FillChars map[rune]rune
+}
+
+// DefaultGIFOptions returns default GIF generation options: 8x16 cells in the
+// terminal aspect ratio, ten frames a second, looping forever
+func DefaultGIFOptions() GIFOptions {
+	return GIFOptions{
+		CellWidth:  8,
+		CellHeight: 16,
+		Delay:      DefaultGIFDelay,
+	}
+}
+
+// GenerateGIF creates an animated GIF from frames of rendered ANSI lines.
+// Frames of different sizes are placed at the top-left of an image as large
+// as the largest. Returns GIF data as bytes or error.
+func GenerateGIF(frames [][]string, options GIFOptions) ([]byte, error) {
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no content to export")
+	}
+
+	// Use defaults if zero values provided
+	if options.CellWidth <= 0 {
+		options.CellWidth = 8
+	}
+	if options.CellHeight <= 0 {
+		options.CellHeight = 16
+	}
+	if options.Delay <= 0 {
+		options.Delay = DefaultGIFDelay
+	}
+
+	cellOptions := PNGOptions{CellWidth: options.CellWidth, CellHeight: options.CellHeight, FillChars: options.FillChars}
+	images := make([]*image.RGBA, len(frames))
+	var width, height int
+	for i, lines := range frames {
+		images[i] = rasterizeLines(lines, cellOptions, color.RGBA{})
+		width = max(width, images[i].Bounds().Dx())
+		height = max(height, images[i].Bounds().Dy())
+	}
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("no content to export")
+	}
+
+	palette := gifPalette(images)
+	animation := &gif.GIF{LoopCount: options.LoopCount}
+	for i, img := range images {
+		paletted := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+		draw.Draw(paletted, img.Bounds(), img, image.Point{}, draw.Src)
+
+		delay := options.Delay
+		if i < len(options.Delays) && options.Delays[i] > 0 {
+			delay = options.Delays[i]
+		}
+		animation.Image = append(animation.Image, paletted)
+		animation.Delay = append(animation.Delay, delay)
+		// Clear each frame before the next so transparent pixels stay transparent
+		animation.Disposal = append(animation.Disposal, gif.DisposalBackground)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, animation); err != nil {
+		return nil, fmt.Errorf("failed to encode GIF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// gifPalette builds the palette shared by all frames. Transparency comes
+// first, then every opaque color when they fit; otherwise colors are reduced
+// to 5 bits per channel and the most used groups are kept as their average.
+func gifPalette(images []*image.RGBA) color.Palette {
+	counts := make(map[color.RGBA]int)
+	for _, img := range images {
+		for i := 0; i+3 < len(img.Pix); i += 4 {
+			if img.Pix[i+3] == 0 {
+				continue
+			}
+			counts[color.RGBA{R: img.Pix[i], G: img.Pix[i+1], B: img.Pix[i+2], A: 255}]++
+		}
+	}
+
+	palette := color.Palette{color.RGBA{}}
+	if len(counts) <= gifMaxColors {
+		colors := make([]color.RGBA, 0, len(counts))
+		for c := range counts {
+			colors = append(colors, c)
+		}
+		sortColorsByCount(colors, counts)
+		for _, c := range colors {
+			palette = append(palette, c)
+		}
+		return palette
+	}
+
+	// Group colors by their top 5 bits per channel, summing each group
+	type colorGroup struct {
+		r, g, b, count int
+	}
+	groups := make(map[color.RGBA]*colorGroup)
+	for c, count := range counts {
+		key := color.RGBA{R: c.R &^ 7, G: c.G &^ 7, B: c.B &^ 7, A: 255}
+		group, ok := groups[key]
+		if !ok {
+			group = &colorGroup{}
+			groups[key] = group
+		}
+		group.r += int(c.R) * count
+		group.g += int(c.G) * count
+		group.b += int(c.B) * count
+		group.count += count
+	}
+
+	keys := make([]color.RGBA, 0, len(groups))
+	groupCounts := make(map[color.RGBA]int, len(groups))
+	for key, group := range groups {
+		keys = append(keys, key)
+		groupCounts[key] = group.count
+	}
+	sortColorsByCount(keys, groupCounts)
+	for _, key := range keys[:min(len(keys), gifMaxColors)] {
+		group := groups[key]
+		palette = append(palette, color.RGBA{
+			R: uint8(group.r / group.count),
+			G: uint8(group.g / group.count),
+			B: uint8(group.b / group.count),
+			A: 255,
+		})
+	}
+	return palette
+}
+
+// sortColorsByCount sorts colors from the most to the least used, breaking
+// ties by value so the palette is the same on every run
+func sortColorsByCount(colors []color.RGBA, counts map[color.RGBA]int) {
+	sort.Slice(colors, func(i, j int) bool {
+		a, b := colors[i], colors[j]
+		if counts[a] != counts[b] {
+			return counts[a] > counts[b]
+		}
+		if a.R != b.R {
+			return a.R < b.R
+		}
+		if a.G != b.G {
+			return a.G < b.G
+		}
+		return a.B < b.B
+	})
+}
@@ -0,0 +1,111 @@
+// ABOUTME: Tests for animated GIF generation from frames of ANSI-colored text.
+// ABOUTME: Verifies frame sizes, delays, loop count, transparency and palette reduction.
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/gif"
+	"strings"
+	"testing"
+)
+
+func decodeGIF(t *testing.T, data []byte) *gif.GIF {
+	t.Helper()
+	animation, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode GIF: %v", err)
+	}
+	return animation
+}
+
+func TestGenerateGIF_EmptyInput(t *testing.T) {
+	_, err := GenerateGIF(nil, DefaultGIFOptions())
+	if err == nil {
+		t.Error("expected error for empty input, got nil")
+	}
+}
+
+func TestGenerateGIF_Frames(t *testing.T) {
+	// Frames of different sizes share the size of the largest
+	frames := [][]string{
+		{"\x1b[38;2;255;0;0m█\x1b[0m"},
+		{"\x1b[38;2;255;0;0m██\x1b[0m", "\x1b[38;2;0;0;255m██\x1b[0m"},
+	}
+
+	data, err := GenerateGIF(frames, DefaultGIFOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	animation := decodeGIF(t, data)
+	if len(animation.Image) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(animation.Image))
+	}
+	for i, frame := range animation.Image {
+		if frame.Bounds().Dx() != 16 || frame.Bounds().Dy() != 32 {
+			t.Errorf("frame %d: expected 16x32, got %dx%d", i, frame.Bounds().Dx(), frame.Bounds().Dy())
+		}
+	}
+}
+
+func TestGenerateGIF_DelaysAndLoopCount(t *testing.T) {
+	frames := [][]string{{"█"}, {"█"}, {"█"}}
+	options := DefaultGIFOptions()
+	options.Delay = 5
+	options.Delays = []int{0, 50}
+	options.LoopCount = 3
+
+	data, err := GenerateGIF(frames, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	animation := decodeGIF(t, data)
+	if fmt.Sprint(animation.Delay) != "[5 50 5]" {
+		t.Errorf("expected delays [5 50 5], got %v", animation.Delay)
+	}
+	if animation.LoopCount != 3 {
+		t.Errorf("expected loop count 3, got %d", animation.LoopCount)
+	}
+}
+
+func TestGenerateGIF_Transparency(t *testing.T) {
+	frames := [][]string{{"\x1b[38;2;0;255;0m▀\x1b[0m"}}
+
+	data, err := GenerateGIF(frames, DefaultGIFOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	frame := decodeGIF(t, data).Image[0]
+	if _, _, _, a := frame.At(0, 0).RGBA(); a == 0 {
+		t.Error("expected the top half to be opaque")
+	}
+	if _, _, _, a := frame.At(0, 15).RGBA(); a != 0 {
+		t.Error("expected the bottom half to be transparent")
+	}
+}
+
+func TestGenerateGIF_ReducesPalette(t *testing.T) {
+	// 300 distinct colors do not fit a GIF palette
+	var line strings.Builder
+	for i := range 300 {
+		fmt.Fprintf(&line, "\x1b[38;2;%d;%d;0m█", i%256, i/256*128)
+	}
+
+	data, err := GenerateGIF([][]string{{line.String()}}, DefaultGIFOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	frame := decodeGIF(t, data).Image[0]
+	if len(frame.Palette) > 256 {
+		t.Errorf("expected at most 256 colors, got %d", len(frame.Palette))
+	}
+	if c := color.RGBAModel.Convert(frame.At(0, 0)).(color.RGBA); c.R > 8 || c.G != 0 || c.A != 255 {
+		t.Errorf("expected the first cell close to black, got %v", c)
+	}
+}
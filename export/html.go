@@ -0,0 +1,154 @@
+// ABOUTME: HTML generator that converts ANSI-colored text to a <pre> block of styled spans.
+// ABOUTME: Runs of one color merge into a single span, styled inline or through CSS classes.
+
+package export
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"strings"
+)
+
+// DefaultHTMLClassPrefix is the prefix of the generated CSS class names
+const DefaultHTMLClassPrefix = "bit"
+
+// HTMLOptions contains configuration for HTML generation
+type HTMLOptions struct {
+	Fragment    bool   // Emit only the block, without the surrounding document, for embedding
+	UseClasses  bool   // Color spans through CSS classes instead of inline styles
+	ClassPrefix string // Prefix of the generated class names (default: DefaultHTMLClassPrefix)
+	Background  string // Hex background color of the block (empty keeps it transparent)
+	Title       string // Document title (default: "bit")
+}
+
+// DefaultHTMLOptions returns default HTML generation options: a standalone
+// document with inline styles
+func DefaultHTMLOptions() HTMLOptions {
+	return HTMLOptions{
+		ClassPrefix: DefaultHTMLClassPrefix,
+		Title:       "bit",
+	}
+}
+
+// htmlStyle is the color pair of a span
+type htmlStyle struct {
+	fg, bg color.RGBA
+}
+
+// css returns the declarations of the style
+func (s htmlStyle) css() string {
+	declarations := "color:" + hexColor(s.fg)
+	if s.bg.A != 0 {
+		declarations += ";background-color:" + hexColor(s.bg)
+	}
+	return declarations
+}
+
+// GenerateHTML creates an HTML document, or an embeddable fragment, from
+// rendered ANSI lines. Returns the HTML or error.
+func GenerateHTML(lines []string, options HTMLOptions) (string, error) {
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no content to export")
+	}
+
+	// Use defaults if zero values provided
+	if options.ClassPrefix == "" {
+		options.ClassPrefix = DefaultHTMLClassPrefix
+	}
+	if options.Title == "" {
+		options.Title = "bit"
+	}
+
+	blockCSS := "font-family:monospace;line-height:1;letter-spacing:0"
+	if options.Background != "" {
+		background, err := parseHexColor(options.Background)
+		if err != nil {
+			return "", err
+		}
+		blockCSS += ";background-color:" + hexColor(background)
+	}
+
+	// Classes are numbered in the order their styles first appear
+	var classStyles []htmlStyle
+	classes := make(map[htmlStyle]string)
+	spanAttribute := func(style htmlStyle) string {
+		if !options.UseClasses {
+			return fmt.Sprintf(`style="%s"`, style.css())
+		}
+		class, ok := classes[style]
+		if !ok {
+			class = fmt.Sprintf("%s-%d", options.ClassPrefix, len(classStyles))
+			classes[style] = class
+			classStyles = append(classStyles, style)
+		}
+		return fmt.Sprintf(`class="%s"`, class)
+	}
+
+	var body strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			body.WriteString("\n")
+		}
+		writeHTMLLine(&body, line, spanAttribute)
+	}
+
+	var b strings.Builder
+	if !options.Fragment {
+		b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+		fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(options.Title))
+	}
+	if options.UseClasses {
+		b.WriteString("<style>\n")
+		fmt.Fprintf(&b, ".%s{%s}\n", options.ClassPrefix, blockCSS)
+		for _, style := range classStyles {
+			fmt.Fprintf(&b, ".%s{%s}\n", classes[style], style.css())
+		}
+		b.WriteString("</style>\n")
+	}
+	if !options.Fragment {
+		b.WriteString("</head>\n<body>\n")
+	}
+	if options.UseClasses {
+		fmt.Fprintf(&b, `<pre class="%s">`, options.ClassPrefix)
+	} else {
+		fmt.Fprintf(&b, `<pre style="%s">`, blockCSS)
+	}
+	b.WriteString(body.String())
+	b.WriteString("</pre>\n")
+	if !options.Fragment {
+		b.WriteString("</body>\n</html>\n")
+	}
+	return b.String(), nil
+}
+
+// writeHTMLLine writes one rendered line as text and spans. Consecutive cells
+// of one style share a span, and blank cells join the span around them rather
+// than splitting it.
+func writeHTMLLine(b *strings.Builder, line string, spanAttribute func(htmlStyle) string) {
+	var current *htmlStyle
+	var run strings.Builder
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		if current == nil {
+			b.WriteString(run.String())
+		} else {
+			fmt.Fprintf(b, "<span %s>%s</span>", spanAttribute(*current), run.String())
+		}
+		run.Reset()
+	}
+
+	for _, cell := range parseANSILine(line) {
+		if cell.char != ' ' || cell.bg.A != 0 {
+			style := htmlStyle{fg: cell.fg, bg: cell.bg}
+			if current == nil || *current != style {
+				flush()
+				current = &style
+			}
+		}
+		run.WriteString(html.EscapeString(string(cell.char)))
+	}
+	flush()
+}
@@ -0,0 +1,105 @@
+// ABOUTME: Tests for HTML generation from ANSI-colored text output.
+// ABOUTME: Verifies span merging, escaping, CSS classes and fragment output.
+
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateHTML_EmptyInput(t *testing.T) {
+	_, err := GenerateHTML([]string{}, DefaultHTMLOptions())
+	if err == nil {
+		t.Error("expected error for empty input, got nil")
+	}
+}
+
+func TestGenerateHTML_Document(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m█\x1b[0m"}
+
+	page, err := GenerateHTML(lines, DefaultHTMLOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(page, "<!DOCTYPE html>") || !strings.Contains(page, "</html>") {
+		t.Errorf("expected a standalone document, got %q", page)
+	}
+	if !strings.Contains(page, `<span style="color:#ff0000">█</span>`) {
+		t.Errorf("expected a red span, got %q", page)
+	}
+}
+
+func TestGenerateHTML_Fragment(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m█\x1b[0m"}
+	options := DefaultHTMLOptions()
+	options.Fragment = true
+
+	page, err := GenerateHTML(lines, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(page, "<pre") || strings.Contains(page, "<html>") {
+		t.Errorf("expected only the <pre> block, got %q", page)
+	}
+}
+
+func TestGenerateHTML_MergesRuns(t *testing.T) {
+	// Same-color cells separated by a blank share one span
+	lines := []string{"\x1b[38;2;0;255;0m█\x1b[0m \x1b[38;2;0;255;0m█\x1b[0m\x1b[38;2;0;0;255m█\x1b[0m"}
+
+	page, err := GenerateHTML(lines, DefaultHTMLOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(page, `<span style="color:#00ff00">█ █</span><span style="color:#0000ff">█</span>`) {
+		t.Errorf("expected two merged spans, got %q", page)
+	}
+}
+
+func TestGenerateHTML_Background(t *testing.T) {
+	lines := []string{"\x1b[48;2;0;0;68m\x1b[38;2;255;255;255m█\x1b[0m"}
+
+	page, err := GenerateHTML(lines, DefaultHTMLOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(page, `style="color:#ffffff;background-color:#000044"`) {
+		t.Errorf("expected the cell background in the span, got %q", page)
+	}
+}
+
+func TestGenerateHTML_Classes(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m█\x1b[0m", "\x1b[38;2;255;0;0m▀\x1b[0m"}
+	options := DefaultHTMLOptions()
+	options.UseClasses = true
+
+	page, err := GenerateHTML(lines, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(page, ".bit-0{color:#ff0000}") {
+		t.Errorf("expected a class rule for red, got %q", page)
+	}
+	if strings.Count(page, `class="bit-0"`) != 2 || strings.Contains(page, "bit-1") {
+		t.Errorf("expected both lines to reuse one class, got %q", page)
+	}
+}
+
+func TestGenerateHTML_EscapesText(t *testing.T) {
+	lines := []string{"<a&b>"}
+
+	page, err := GenerateHTML(lines, DefaultHTMLOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(page, "&lt;a&amp;b&gt;") {
+		t.Errorf("expected escaped text, got %q", page)
+	}
+}
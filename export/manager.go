@@ -1,5 +1,5 @@
 // ABOUTME: Export manager handles saving rendered ANSI art to various file formats.
-// ABOUTME: Supports text formats (TXT, GO, JS, PY, RS, SH) and binary formats (PNG).
+// ABOUTME: Lists the formats of the exporter registry, text (TXT, SVG, HTML, code) and binary (PNG, GIF, ANS).
 
 package export
 
@@ -29,10 +29,16 @@ type ExportManager struct {
 	basePath string // Base directory for exports (defaults to Desktop)
 }
 
-// NewExportManager creates a new export manager with supported formats
+// NewExportManager creates a new export manager with the formats of the
+// exporters registered at the time
 func NewExportManager() *ExportManager {
+	exporters := Exporters()
+	formats := make([]ExportFormat, len(exporters))
+	for i, exporter := range exporters {
+		formats[i] = formatOf(exporter)
+	}
 	return &ExportManager{
-		formats:  SupportedFormats,
+		formats:  formats,
 		basePath: getCWD(),
 	}
 }
@@ -302,149 +308,25 @@ func GenerateTXTCode(lines []string) string {
 
 // GenerateGoCode creates Go source code that reproduces the ANSI art
 func GenerateGoCode(lines []string) string {
-	var builder strings.Builder
-
-	// Write package and imports (minimal imports for standalone version)
-	builder.WriteString("package main\n\n")
-	builder.WriteString("import (\n")
-	builder.WriteString("\t\"fmt\"\n")
-	builder.WriteString(")\n\n")
-
-	// Write main function
-	builder.WriteString("func main() {\n")
-
-	// Add the rendered lines with embedded ANSI codes
-	builder.WriteString("\tlines := []string{\n")
-	for _, line := range lines {
-		// Escape quotes in the line
-		escapedLine := strings.ReplaceAll(line, "\"", "\\\"")
-		builder.WriteString(fmt.Sprintf("\t\t\"%s\",\n", escapedLine))
-	}
-	builder.WriteString("\t}\n\n")
-
-	// Print each line
-	builder.WriteString("\tfor _, line := range lines {\n")
-	builder.WriteString("\t\tfmt.Println(line)\n")
-	builder.WriteString("\t}\n")
-	builder.WriteString("}\n")
-
-	return builder.String()
+	return generateBuiltinCode("go.tmpl", lines)
 }
 
 // GenerateJSCode creates JavaScript source code that reproduces the ANSI art
 func GenerateJSCode(lines []string) string {
-	var builder strings.Builder
-
-	// Write file header
-	builder.WriteString("/* Generated JavaScript ANSI Art */\n")
-	builder.WriteString("\n")
-
-	// Write the array of lines
-	builder.WriteString("const ansiArtLines = [\n")
-	for _, line := range lines {
-		// Escape quotes and backslashes in the line
-		escapedLine := strings.ReplaceAll(line, "\\", "\\\\")
-		escapedLine = strings.ReplaceAll(escapedLine, "\"", "\\\"")
-		builder.WriteString(fmt.Sprintf("  \"%s\",\n", escapedLine))
-	}
-	builder.WriteString("];\n\n")
-
-	// Write function to display the art
-	builder.WriteString("function displayAnsiArt() {\n")
-	builder.WriteString("  ansiArtLines.forEach(function(line) {\n")
-	builder.WriteString("    console.log(line);\n")
-	builder.WriteString("  });\n")
-	builder.WriteString("}\n\n")
-	builder.WriteString("displayAnsiArt();\n")
-
-	return builder.String()
+	return generateBuiltinCode("js.tmpl", lines)
 }
 
 // GeneratePythonCode creates Python source code that reproduces the ANSI art
 func GeneratePythonCode(lines []string) string {
-	var builder strings.Builder
-
-	// Write file header
-	builder.WriteString("# Generated Python ANSI Art\n")
-	builder.WriteString("\n")
-
-	// Write the array of lines
-	builder.WriteString("ansi_art_lines = [\n")
-	for _, line := range lines {
-		// Escape quotes and backslashes in the line
-		escapedLine := strings.ReplaceAll(line, "\\", "\\\\")
-		escapedLine = strings.ReplaceAll(escapedLine, "\"", "\\\"")
-		escapedLine = strings.ReplaceAll(escapedLine, "'", "\\'")
-		builder.WriteString(fmt.Sprintf("    \"%s\",\n", escapedLine))
-	}
-	builder.WriteString("]\n\n")
-
-	// Write function to display the art
-	builder.WriteString("def display_ansi_art():\n")
-	builder.WriteString("    for line in ansi_art_lines:\n")
-	builder.WriteString("        print(line)\n\n")
-	builder.WriteString("if __name__ == \"__main__\":\n")
-	builder.WriteString("    display_ansi_art()\n")
-
-	return builder.String()
+	return generateBuiltinCode("py.tmpl", lines)
 }
 
 // GenerateRustCode creates Rust source code that reproduces the ANSI art
 func GenerateRustCode(lines []string) string {
-	var builder strings.Builder
-
-	// Write file header
-	builder.WriteString("// Generated Rust ANSI Art\n")
-	builder.WriteString("fn main() {\n")
-	builder.WriteString("    let ansi_art_lines = vec![\n")
-
-	// Write the array of lines
-	for _, line := range lines {
-		// Escape quotes and backslashes in the line
-		escapedLine := strings.ReplaceAll(line, "\\", "\\\\")
-		escapedLine = strings.ReplaceAll(escapedLine, "\"", "\\\"")
-		builder.WriteString(fmt.Sprintf("        \"%s\",\n", escapedLine))
-	}
-	builder.WriteString("    ];\n\n")
-
-	// Write code to display the art
-	builder.WriteString("    for line in ansi_art_lines {\n")
-	builder.WriteString("        println!(\"{}\", line);\n")
-	builder.WriteString("    }\n")
-	builder.WriteString("}\n")
-
-	return builder.String()
+	return generateBuiltinCode("rs.tmpl", lines)
 }
 
 // GenerateBashCode creates Bash script that reproduces the ANSI art
 func GenerateBashCode(lines []string) string {
-	var builder strings.Builder
-
-	// Write file header
-	builder.WriteString("#!/bin/bash\n")
-	builder.WriteString("# Generated Bash ANSI Art\n")
-	builder.WriteString("\n")
-
-	// Write array of lines
-	builder.WriteString("ansi_art_lines=(\n")
-	for _, line := range lines {
-		// Escape quotes and backslashes in the line
-		escapedLine := strings.ReplaceAll(line, "\\", "\\\\")
-		escapedLine = strings.ReplaceAll(escapedLine, "\"", "\\\"")
-		escapedLine = strings.ReplaceAll(escapedLine, "$", "\\$")
-		escapedLine = strings.ReplaceAll(escapedLine, "`", "\\`")
-		builder.WriteString(fmt.Sprintf("    \"%s\"\n", escapedLine))
-	}
-	builder.WriteString(")\n\n")
-
-	// Write function to display the art
-	builder.WriteString("display_ansi_art() {\n")
-	builder.WriteString("    for line in \"${ansi_art_lines[@]}\"; do\n")
-	builder.WriteString("        echo -e \"$line\"\n")
-	builder.WriteString("    done\n")
-	builder.WriteString("}\n\n")
-	builder.WriteString("# Call the function\n")
-	builder.WriteString("display_ansi_art\n")
-
-	return builder.String()
+	return generateBuiltinCode("sh.tmpl", lines)
 }
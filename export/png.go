@@ -0,0 +1,410 @@
+// ABOUTME: PNG generator that converts ANSI-colored text to PNG images.
+// ABOUTME: Parses ANSI escape sequences and renders characters at 16x scale with transparency.
+// ABOUTME: Background colors and box-drawing frames from the renderer are drawn as well.
+// ABOUTME: Options add a background, padding, pixel-perfect cells, output scaling and dithered shades.
+
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"unicode/utf8"
+)
+
+// CellSize defines the default pixel dimensions per terminal character cell.
+// 4x scale per dimension = 16x16 pixels per cell.
+const CellSize = 16
+
+// Unicode block characters
+const (
+	FullBlock       = '█' // U+2588
+	UpperHalfBlock  = '▀' // U+2580
+	LowerHalfBlock  = '▄' // U+2584
+	LightShade      = '░' // U+2591
+	MediumShade     = '▒' // U+2592
+	DarkShade       = '▓' // U+2593
+)
+
+// Brightness multipliers for shade characters (0.0 - 1.0)
+const (
+	LightShadeBrightness  = 0.25 // ~25%
+	MediumShadeBrightness = 0.50 // ~50%
+	DarkShadeBrightness   = 0.75 // ~75%
+)
+
+// shadeBrightness maps the shade characters to their brightness
+var shadeBrightness = map[rune]float64{
+	LightShade:  LightShadeBrightness,
+	MediumShade: MediumShadeBrightness,
+	DarkShade:   DarkShadeBrightness,
+}
+
+// Matches any ANSI escape sequence (for stripping)
+var ansiStripRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// PNGOptions contains configuration for PNG generation
+type PNGOptions struct {
+	CellWidth  int // Pixels per character cell width (default: CellSize)
+	CellHeight int // Pixels per character cell height (default: CellSize)
+
+	// PixelSize switches to pixel-perfect cells PixelSize wide and twice as
+	// tall, so every font pixel is a PixelSize square (overrides CellWidth and CellHeight)
+	PixelSize int
+
+	Scale        int    // Whole-number factor the finished image is enlarged by (default: 1)
+	Background   string // Hex background color (empty keeps the background transparent)
+	Padding      int    // Pixels of background around the text, after scaling
+	DitherShades bool   // Draw ░▒▓ as dither patterns over the background instead of darkened solid fills
+
+	// FillChars maps custom fill characters (e.g. '#' in ASCII output) back to
+	// the block or shade character they replace, so they draw with the same shape.
+	FillChars map[rune]rune
+}
+
+// DefaultPNGOptions returns default PNG generation options (16x16 per cell)
+func DefaultPNGOptions() PNGOptions {
+	return PNGOptions{
+		CellWidth:  CellSize,
+		CellHeight: CellSize,
+	}
+}
+
+// TerminalAspectRatioPNGOptions returns PNG options that match terminal character
+// aspect ratio. Terminal characters are typically ~2:1 (height:width), so each
+// character cell is rendered twice as tall as it is wide.
+func TerminalAspectRatioPNGOptions() PNGOptions {
+	return PNGOptions{
+		CellWidth:  16, // 16 pixels wide (4x scale)
+		CellHeight: 32, // 32 pixels tall (2:1 height:width ratio)
+	}
+}
+
+// GeneratePNG creates a PNG image from rendered ANSI lines.
+// Returns PNG data as bytes or error.
+func GeneratePNG(lines []string, options PNGOptions) ([]byte, error) {
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no content to export")
+	}
+
+	// Use defaults if zero values provided
+	if options.PixelSize > 0 {
+		options.CellWidth = options.PixelSize
+		options.CellHeight = options.PixelSize * 2
+	}
+	if options.CellWidth == 0 {
+		options.CellWidth = CellSize
+	}
+	if options.CellHeight == 0 {
+		options.CellHeight = CellSize
+	}
+	if options.Scale <= 0 {
+		options.Scale = 1
+	}
+
+	var background color.RGBA
+	if options.Background != "" {
+		var err error
+		if background, err = parseHexColor(options.Background); err != nil {
+			return nil, err
+		}
+	}
+
+	img := frameImage(rasterizeLines(lines, options, background), options.Scale, options.Padding, background)
+
+	// Encode to PNG
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// frameImage enlarges img by scale, surrounds it with padding pixels and puts
+// it on the background. A transparent background keeps img as it is.
+func frameImage(img *image.RGBA, scale, padding int, background color.RGBA) *image.RGBA {
+	padding = max(padding, 0)
+	if scale == 1 && padding == 0 && background.A == 0 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	framed := image.NewRGBA(image.Rect(0, 0, bounds.Dx()*scale+padding*2, bounds.Dy()*scale+padding*2))
+	if background.A != 0 {
+		fillRect(framed, 0, 0, framed.Bounds().Dx(), framed.Bounds().Dy(), background)
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if c := img.RGBAAt(x, y); c.A != 0 {
+				fillRect(framed, padding+x*scale, padding+y*scale, scale, scale, c)
+			}
+		}
+	}
+	return framed
+}
+
+// rasterizeLines draws rendered ANSI lines onto a transparent image, one cell
+// of the options' size per character. background is the color behind cells
+// without a background of their own, which shades blend toward; the zero value
+// blends them toward black.
+func rasterizeLines(lines []string, options PNGOptions, background color.RGBA) *image.RGBA {
+	// Calculate image dimensions based on max line width
+	maxWidth := 0
+	for _, line := range lines {
+		lineWidth := countVisibleChars(line)
+		if lineWidth > maxWidth {
+			maxWidth = lineWidth
+		}
+	}
+
+	// Handle edge case of all-empty lines
+	if maxWidth == 0 {
+		maxWidth = 1
+	}
+
+	imgWidth := maxWidth * options.CellWidth
+	imgHeight := len(lines) * options.CellHeight
+
+	// Create RGBA image with transparent background (zero-initialized = transparent)
+	img := image.NewRGBA(image.Rect(0, 0, imgWidth, imgHeight))
+
+	rows := make([][]ansiCell, len(lines))
+	for lineIdx, line := range lines {
+		rows[lineIdx] = parseANSILine(line)
+	}
+	asciiFrame := hasASCIIFrame(rows)
+
+	// Render each line
+	for lineIdx, cells := range rows {
+		frameRow := asciiFrame && (lineIdx == 0 || lineIdx == len(rows)-1)
+		renderLineToImage(img, cells, lineIdx, options, background, asciiFrame, frameRow)
+	}
+	return img
+}
+
+// hasASCIIFrame reports whether rows are surrounded by an ASCII frame, which
+// puts '+' in all four corners
+func hasASCIIFrame(rows [][]ansiCell) bool {
+	if len(rows) < 2 {
+		return false
+	}
+	for _, row := range [][]ansiCell{rows[0], rows[len(rows)-1]} {
+		if len(row) < 2 || row[0].char != '+' || row[len(row)-1].char != '+' {
+			return false
+		}
+	}
+	return true
+}
+
+// renderLineToImage renders a single line of ANSI cells to the image. With
+// asciiFrame, the first and last cells and every cell of a frameRow belong to
+// the frame, so their '-', '|' and '+' draw as frame lines.
+func renderLineToImage(img *image.RGBA, cells []ansiCell, lineIdx int, options PNGOptions, background color.RGBA, asciiFrame, frameRow bool) {
+	for charIdx, cell := range cells {
+		// Render the background, then the character on top of it
+		shadeBg := background
+		if cell.bg.A != 0 {
+			fillRect(img, charIdx*options.CellWidth, lineIdx*options.CellHeight, options.CellWidth, options.CellHeight, cell.bg)
+			shadeBg = cell.bg
+		}
+		onFrame := frameRow || (asciiFrame && (charIdx == 0 || charIdx == len(cells)-1))
+		drawCell(img, charIdx, lineIdx, cell.char, cell.fg, shadeBg, onFrame, options)
+	}
+}
+
+// drawCell draws a single character cell to the image. Shades blend the color
+// toward the background, which is black when the background is transparent.
+// The ASCII frame characters draw as lines only onFrame; elsewhere they are
+// glyph letters or fill characters and draw as blocks.
+func drawCell(img *image.RGBA, x, y int, char rune, c color.RGBA, bg color.RGBA, onFrame bool, options PNGOptions) {
+	cellX := x * options.CellWidth
+	cellY := y * options.CellHeight
+	halfHeight := options.CellHeight / 2
+
+	// Draw custom fill characters as the block or shade they stand for
+	if original, ok := options.FillChars[char]; ok {
+		char = original
+	}
+
+	switch char {
+	case FullBlock:
+		// Fill entire cell
+		fillRect(img, cellX, cellY, options.CellWidth, options.CellHeight, c)
+
+	case UpperHalfBlock:
+		// Fill top half only
+		fillRect(img, cellX, cellY, options.CellWidth, halfHeight, c)
+
+	case LowerHalfBlock:
+		// Fill bottom half only
+		fillRect(img, cellX, cellY+halfHeight, options.CellWidth, halfHeight, c)
+
+	case LightShade, MediumShade, DarkShade:
+		brightness := shadeBrightness[char]
+		if options.DitherShades {
+			ditherRect(img, cellX, cellY, options.CellWidth, options.CellHeight, c, brightness, max(1, options.CellWidth/4))
+		} else {
+			fillRect(img, cellX, cellY, options.CellWidth, options.CellHeight, blendShade(c, bg, brightness))
+		}
+
+	case ' ':
+		// Space - leave transparent (do nothing)
+
+	default:
+		if arms, ok := boxDrawingArms[char]; ok {
+			drawBoxArms(img, cellX, cellY, arms, c, options)
+			return
+		}
+		if arms, ok := asciiFrameArms[char]; ok && onFrame {
+			drawBoxArms(img, cellX, cellY, arms, c, options)
+			return
+		}
+
+		// For any other printable character, fill as full block
+		// This handles edge cases where other characters might be used
+		if char > 32 { // Printable ASCII/Unicode
+			fillRect(img, cellX, cellY, options.CellWidth, options.CellHeight, c)
+		}
+		// Non-printable or control chars: leave transparent
+	}
+}
+
+// ditherMatrix is the 2x2 ordered dither threshold matrix
+var ditherMatrix = [2][2]float64{{0, 2}, {3, 1}}
+
+// ditherRect sets the share of dots of a rectangle given by coverage to the
+// color and leaves the others untouched. Dots are dotSize pixels and follow a
+// pattern anchored at the image origin, so neighboring cells tile seamlessly.
+func ditherRect(img *image.RGBA, x, y, width, height int, c color.RGBA, coverage float64, dotSize int) {
+	for py := y; py < y+height; py++ {
+		for px := x; px < x+width; px++ {
+			if (ditherMatrix[(py/dotSize)%2][(px/dotSize)%2]+0.5)/4 < coverage {
+				fillRect(img, px, py, 1, 1, c)
+			}
+		}
+	}
+}
+
+// blendShade mixes a shade's color toward the background by brightness
+func blendShade(c, bg color.RGBA, brightness float64) color.RGBA {
+	return color.RGBA{
+		R: uint8(float64(bg.R) + (float64(c.R)-float64(bg.R))*brightness),
+		G: uint8(float64(bg.G) + (float64(c.G)-float64(bg.G))*brightness),
+		B: uint8(float64(bg.B) + (float64(c.B)-float64(bg.B))*brightness),
+		A: 255,
+	}
+}
+
+// Line weights of box-drawing arms
+const (
+	noArm = iota
+	lightArm
+	heavyArm
+	doubleArm
+)
+
+// boxArms lists the line weight reaching from the cell center to each edge
+type boxArms struct {
+	up, down, left, right int
+}
+
+// boxDrawingArms covers the frame characters produced by the renderer
+var boxDrawingArms = map[rune]boxArms{
+	'─': {left: lightArm, right: lightArm},
+	'│': {up: lightArm, down: lightArm},
+	'┌': {down: lightArm, right: lightArm},
+	'┐': {down: lightArm, left: lightArm},
+	'└': {up: lightArm, right: lightArm},
+	'┘': {up: lightArm, left: lightArm},
+	'╭': {down: lightArm, right: lightArm},
+	'╮': {down: lightArm, left: lightArm},
+	'╰': {up: lightArm, right: lightArm},
+	'╯': {up: lightArm, left: lightArm},
+	'━': {left: heavyArm, right: heavyArm},
+	'┃': {up: heavyArm, down: heavyArm},
+	'┏': {down: heavyArm, right: heavyArm},
+	'┓': {down: heavyArm, left: heavyArm},
+	'┗': {up: heavyArm, right: heavyArm},
+	'┛': {up: heavyArm, left: heavyArm},
+	'═': {left: doubleArm, right: doubleArm},
+	'║': {up: doubleArm, down: doubleArm},
+	'╔': {down: doubleArm, right: doubleArm},
+	'╗': {down: doubleArm, left: doubleArm},
+	'╚': {up: doubleArm, right: doubleArm},
+	'╝': {up: doubleArm, left: doubleArm},
+}
+
+// asciiFrameArms covers the characters of the ASCII frame, which glyph letters
+// and fill characters may also use
+var asciiFrameArms = map[rune]boxArms{
+	'-': {left: lightArm, right: lightArm},
+	'|': {up: lightArm, down: lightArm},
+	'+': {up: lightArm, down: lightArm, left: lightArm, right: lightArm},
+}
+
+// drawBoxArms draws the lines of a box-drawing character from the cell center
+// to the edges named in arms
+func drawBoxArms(img *image.RGBA, cellX, cellY int, arms boxArms, c color.RGBA, options PNGOptions) {
+	thin := max(1, options.CellWidth/8)
+	centerX := cellX + options.CellWidth/2
+	centerY := cellY + options.CellHeight/2
+
+	// offsets returns the start offsets and thickness of the strokes of one arm
+	offsets := func(weight int) ([]int, int) {
+		switch weight {
+		case lightArm:
+			return []int{-thin / 2}, thin
+		case heavyArm:
+			return []int{-thin}, thin * 2
+		case doubleArm:
+			return []int{-thin * 3 / 2, thin / 2}, thin
+		}
+		return nil, 0
+	}
+
+	// Strokes overlap the center so perpendicular arms join cleanly
+	reach := thin * 2
+	if starts, width := offsets(arms.left); width > 0 {
+		for _, o := range starts {
+			fillRect(img, cellX, centerY+o, centerX-cellX+reach, width, c)
+		}
+	}
+	if starts, width := offsets(arms.right); width > 0 {
+		for _, o := range starts {
+			fillRect(img, centerX-reach, centerY+o, cellX+options.CellWidth-centerX+reach, width, c)
+		}
+	}
+	if starts, width := offsets(arms.up); width > 0 {
+		for _, o := range starts {
+			fillRect(img, centerX+o, cellY, width, centerY-cellY+reach, c)
+		}
+	}
+	if starts, width := offsets(arms.down); width > 0 {
+		for _, o := range starts {
+			fillRect(img, centerX+o, centerY-reach, width, cellY+options.CellHeight-centerY+reach, c)
+		}
+	}
+}
+
+// fillRect fills a rectangle in the image with the given color
+func fillRect(img *image.RGBA, x, y, width, height int, c color.RGBA) {
+	bounds := img.Bounds()
+	for dy := 0; dy < height; dy++ {
+		for dx := 0; dx < width; dx++ {
+			px, py := x+dx, y+dy
+			if px >= bounds.Min.X && px < bounds.Max.X && py >= bounds.Min.Y && py < bounds.Max.Y {
+				img.SetRGBA(px, py, c)
+			}
+		}
+	}
+}
+
+// countVisibleChars counts visible (non-ANSI) characters in a line
+func countVisibleChars(line string) int {
+	stripped := ansiStripRegex.ReplaceAllString(line, "")
+	return utf8.RuneCountInString(stripped)
+}
@@ -8,6 +8,8 @@ import (
 	"image"
 	"image/png"
 	"testing"
+
+	"github.com/paulilaaso/bit/ansifonts"
 )
 
 func TestGeneratePNG_EmptyInput(t *testing.T) {
@@ -392,3 +394,307 @@ func TestCountVisibleChars(t *testing.T) {
 		})
 	}
 }
+
+func TestGeneratePNG_FillCharsAlias(t *testing.T) {
+	// A custom '"' fill character mapped to the upper half block should only fill the top half
+	lines := []string{"\x1b[38;2;255;0;0m\"\x1b[0m"}
+
+	opts := DefaultPNGOptions()
+	opts.FillChars = map[rune]rune{'"': UpperHalfBlock}
+	data, err := GeneratePNG(lines, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+
+	_, _, _, topA := img.At(CellSize/2, CellSize/4).RGBA()
+	if uint8(topA>>8) == 0 {
+		t.Error("expected top half to be opaque, got transparent")
+	}
+	_, _, _, bottomA := img.At(CellSize/2, CellSize*3/4).RGBA()
+	if uint8(bottomA>>8) != 0 {
+		t.Errorf("expected bottom half to be transparent, got A=%d", uint8(bottomA>>8))
+	}
+}
+
+func TestGeneratePNG_UnmappedFillCharIsFilled(t *testing.T) {
+	// ASCII fill characters without an alias are still drawn as filled pixels
+	lines := []string{"\x1b[38;2;0;255;0m#\x1b[0m"}
+
+	data, err := GeneratePNG(lines, DefaultPNGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+
+	r, g, b, a := img.At(CellSize/2, CellSize*3/4).RGBA()
+	if uint8(a>>8) != 255 || uint8(r>>8) != 0 || uint8(g>>8) != 255 || uint8(b>>8) != 0 {
+		t.Errorf("expected opaque green pixel, got RGBA(%d,%d,%d,%d)", r>>8, g>>8, b>>8, a>>8)
+	}
+}
+
+func TestGeneratePNG_ShadeBrightnessOrdering(t *testing.T) {
+	// Glow rings use all three shades; denser shades must render brighter
+	lines := []string{"\x1b[38;2;255;0;255m▓\x1b[0m\x1b[38;2;255;0;255m▒\x1b[0m\x1b[38;2;255;0;255m░\x1b[0m"}
+
+	data, err := GeneratePNG(lines, DefaultPNGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+
+	var reds [3]uint8
+	for i := range reds {
+		r, _, _, a := img.At(i*CellSize+CellSize/2, CellSize/2).RGBA()
+		if uint8(a>>8) != 255 {
+			t.Errorf("expected shade %d to be fully opaque, got A=%d", i, uint8(a>>8))
+		}
+		reds[i] = uint8(r >> 8)
+	}
+	if !(reds[0] > reds[1] && reds[1] > reds[2]) {
+		t.Errorf("expected dark > medium > light shade brightness, got %v", reds)
+	}
+}
+
+func TestGeneratePNG_BackgroundColor(t *testing.T) {
+	// A background sequence paints the cell, and a reset returns to transparent
+	lines := []string{"\x1b[48;2;0;0;200m \x1b[0m "}
+
+	data, err := GeneratePNG(lines, DefaultPNGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+
+	r, g, b, a := img.At(CellSize/2, CellSize/2).RGBA()
+	if uint8(r>>8) != 0 || uint8(g>>8) != 0 || uint8(b>>8) != 200 || uint8(a>>8) != 255 {
+		t.Errorf("expected background RGBA(0,0,200,255), got RGBA(%d,%d,%d,%d)", r>>8, g>>8, b>>8, a>>8)
+	}
+
+	_, _, _, a = img.At(CellSize+CellSize/2, CellSize/2).RGBA()
+	if uint8(a>>8) != 0 {
+		t.Errorf("expected cell after reset to be transparent, got A=%d", a>>8)
+	}
+}
+
+func TestGeneratePNG_HalfBlockOverBackground(t *testing.T) {
+	// The empty half of a half block shows the background color
+	lines := []string{"\x1b[48;2;0;0;200m\x1b[38;2;255;0;0m▀\x1b[0m"}
+
+	data, err := GeneratePNG(lines, DefaultPNGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+
+	r, _, _, _ := img.At(CellSize/2, CellSize/4).RGBA()
+	if uint8(r>>8) != 255 {
+		t.Errorf("expected red top half, got R=%d", r>>8)
+	}
+	_, _, b, a := img.At(CellSize/2, CellSize*3/4).RGBA()
+	if uint8(b>>8) != 200 || uint8(a>>8) != 255 {
+		t.Errorf("expected blue background in bottom half, got B=%d A=%d", b>>8, a>>8)
+	}
+}
+
+func TestGeneratePNG_BoxDrawingFrame(t *testing.T) {
+	// Frame characters draw thin lines instead of filling the whole cell
+	lines := []string{"\x1b[38;2;255;255;255m─\x1b[0m\x1b[38;2;255;255;255m┌\x1b[0m"}
+
+	data, err := GeneratePNG(lines, DefaultPNGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+
+	if _, _, _, a := img.At(0, CellSize/2).RGBA(); uint8(a>>8) != 255 {
+		t.Errorf("expected horizontal line at the left edge of ─, got A=%d", a>>8)
+	}
+	if _, _, _, a := img.At(CellSize/2, 0).RGBA(); uint8(a>>8) != 0 {
+		t.Errorf("expected ─ to leave the top edge transparent, got A=%d", a>>8)
+	}
+	if _, _, _, a := img.At(CellSize, 0).RGBA(); uint8(a>>8) != 0 {
+		t.Errorf("expected ┌ to leave its top-left corner transparent, got A=%d", a>>8)
+	}
+	if _, _, _, a := img.At(CellSize+CellSize/2, CellSize-1).RGBA(); uint8(a>>8) != 255 {
+		t.Errorf("expected ┌ to reach the bottom edge, got A=%d", a>>8)
+	}
+}
+
+func TestGeneratePNG_ASCIIFrameOnlyOnTheEdge(t *testing.T) {
+	// '-', '|' and '+' draw as lines on an ASCII frame, and as blocks when a
+	// glyph letter or fill character uses them inside it or without a frame
+	tests := []struct {
+		name  string
+		lines []string
+		x, y  int // Cell of the '-'
+		line  bool
+	}{
+		{"frame edge", []string{"+-+", "|-|", "+-+"}, 1, 0, true},
+		{"inside the frame", []string{"+-+", "|-|", "+-+"}, 1, 1, false},
+		{"no frame", []string{"+-", "-+"}, 1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := GeneratePNG(tt.lines, DefaultPNGOptions())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			img, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("failed to decode PNG: %v", err)
+			}
+
+			// A line leaves the top of the cell transparent, a block fills it
+			_, _, _, a := img.At(tt.x*CellSize+CellSize/2, tt.y*CellSize).RGBA()
+			if got := uint8(a>>8) == 0; got != tt.line {
+				t.Errorf("expected line=%v, got A=%d at the top of the cell", tt.line, a>>8)
+			}
+		})
+	}
+}
+
+func TestGeneratePNG_StripePatternPixels(t *testing.T) {
+	// A stripe pattern splits full blocks into two pixel rows of different colors
+	font := ansifonts.FontData{Name: "bar", Characters: map[string][]string{"I": {"█", "█"}}}
+	options := ansifonts.DefaultRenderOptions()
+	options.TextColor = "#FF0000"
+	options.Pattern = ansifonts.StripePattern
+	options.PatternColor = "#0000FF"
+	lines := ansifonts.RenderTextWithFont("I", font, options)
+
+	data, err := GeneratePNG(lines, DefaultPNGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+
+	for row := range 2 {
+		top := img.At(CellSize/2, row*CellSize+CellSize/4)
+		bottom := img.At(CellSize/2, row*CellSize+CellSize*3/4)
+		if r, _, b, _ := top.RGBA(); uint8(r>>8) != 255 || uint8(b>>8) != 0 {
+			t.Errorf("row %d: expected red top pixel, got R=%d B=%d", row, r>>8, b>>8)
+		}
+		if r, _, b, _ := bottom.RGBA(); uint8(r>>8) != 0 || uint8(b>>8) != 255 {
+			t.Errorf("row %d: expected blue bottom pixel, got R=%d B=%d", row, r>>8, b>>8)
+		}
+	}
+}
+
+func decodeTestPNG(t *testing.T, lines []string, opts PNGOptions) image.Image {
+	t.Helper()
+	data, err := GeneratePNG(lines, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+	return img
+}
+
+func TestGeneratePNG_PixelSize(t *testing.T) {
+	// Pixel-perfect cells are one font pixel wide and two tall
+	lines := []string{"\x1b[38;2;255;0;0m▀█\x1b[0m"}
+
+	img := decodeTestPNG(t, lines, PNGOptions{PixelSize: 1})
+
+	if bounds := img.Bounds(); bounds.Dx() != 2 || bounds.Dy() != 2 {
+		t.Fatalf("expected 2x2, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+	if _, _, _, a := img.At(0, 1).RGBA(); a != 0 {
+		t.Error("expected the lower half of ▀ to be transparent")
+	}
+	if _, _, _, a := img.At(1, 1).RGBA(); a == 0 {
+		t.Error("expected █ to fill both pixels")
+	}
+}
+
+func TestGeneratePNG_ScaleAndPadding(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m█\x1b[0m"}
+
+	img := decodeTestPNG(t, lines, PNGOptions{PixelSize: 1, Scale: 3, Padding: 2})
+
+	if bounds := img.Bounds(); bounds.Dx() != 7 || bounds.Dy() != 10 {
+		t.Fatalf("expected 7x10, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+	if _, _, _, a := img.At(1, 1).RGBA(); a != 0 {
+		t.Error("expected transparent padding")
+	}
+	if r, _, _, a := img.At(4, 7).RGBA(); uint8(r>>8) != 255 || a == 0 {
+		t.Error("expected the scaled block inside the padding")
+	}
+}
+
+func TestGeneratePNG_Background(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;255;255m░ \x1b[0m"}
+
+	img := decodeTestPNG(t, lines, PNGOptions{CellWidth: 4, CellHeight: 8, Background: "#0000FF"})
+
+	// Empty cells show the background, and shades blend toward it
+	if r, g, b, _ := img.At(6, 4).RGBA(); uint8(r>>8) != 0 || uint8(g>>8) != 0 || uint8(b>>8) != 255 {
+		t.Errorf("expected a blue background, got RGB(%d,%d,%d)", r>>8, g>>8, b>>8)
+	}
+	if r, _, b, _ := img.At(1, 4).RGBA(); uint8(r>>8) != 63 || uint8(b>>8) != 255 {
+		t.Errorf("expected the shade blended toward blue, got R=%d B=%d", r>>8, b>>8)
+	}
+
+	if _, err := GeneratePNG(lines, PNGOptions{Background: "blue"}); err == nil {
+		t.Error("expected error for invalid background color, got nil")
+	}
+}
+
+func TestGeneratePNG_DitherShades(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;255;255m▒\x1b[0m"}
+
+	img := decodeTestPNG(t, lines, PNGOptions{CellWidth: 8, CellHeight: 16, DitherShades: true})
+
+	// The medium shade is a checkerboard of 2-pixel dots in the full color
+	opaque := 0
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 8; x++ {
+			r, _, _, a := img.At(x, y).RGBA()
+			if a != 0 {
+				opaque++
+				if uint8(r>>8) != 255 {
+					t.Fatalf("expected dots in the full color, got R=%d", r>>8)
+				}
+			}
+			if on := (x/2+y/2)%2 == 0; on != (a != 0) {
+				t.Fatalf("unexpected dot at (%d,%d)", x, y)
+			}
+		}
+	}
+	if opaque != 64 {
+		t.Errorf("expected half of the pixels set, got %d of 128", opaque)
+	}
+}
@@ -0,0 +1,77 @@
+// ABOUTME: MOTD, shell prompt and terminal greeter formats generated from built-in templates.
+// ABOUTME: Escapes lines for printf formats and wraps color codes for zsh and bash prompts.
+
+package export
+
+import "strings"
+
+// ShellOptions contains configuration for the MOTD, prompt and greeter exports
+type ShellOptions struct {
+	NoColorFallback bool // Add a plain variant used when the NO_COLOR environment variable is set
+}
+
+// shellFormats are the MOTD, prompt and greeter formats, listed after the
+// code languages
+var shellFormats = []codeFormat{
+	{"MOTD", ".motd", "Message of the day for /etc/motd", "motd.tmpl", nil},
+	// run-parts skips file names with a dot, so update-motd.d scripts have no extension
+	{"UPDATEMOTD", "", "update-motd.d script", "update-motd.tmpl", nil},
+	{"ZSH", ".zsh", "zsh prompt snippet", "zsh.tmpl", nil},
+	{"BASH", ".bash", "bash prompt snippet", "bash.tmpl", nil},
+	{"FISH", ".fish", "fish_greeting function", "fish.tmpl", nil},
+	{"PSPROFILE", ".profile.ps1", "PowerShell profile snippet (PowerShell 7)", "psprofile.tmpl", nil},
+}
+
+// printf format syntaxes. POSIX printf reads octal escapes; fish's printf
+// also reads hex ones, and fish single quotes keep backslashes other than
+// \\ and \' for printf to read.
+var (
+	printfStrings = stringSyntax{`'`, `'`, map[rune]string{
+		'\\': `\\`, '%': `%%`, '\'': `'\''`, '\n': `\n`, '\r': `\r`, '\t': `\t`,
+	}, `\%03o`}
+	fishPrintfStrings = stringSyntax{`'`, `'`, map[rune]string{
+		'\\': `\\\\`, '%': `%%`, '\'': `\'`, '\n': `\n`, '\r': `\r`, '\t': `\t`,
+	}, `\x%02x`}
+)
+
+// promptSegments calls code for every ANSI escape sequence of lines and text
+// for the text between them, with newline between lines and after the last
+func promptSegments(lines []string, code, text func(string) string, newline string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		last := 0
+		for _, match := range ansiRegex.FindAllStringIndex(line, -1) {
+			b.WriteString(text(line[last:match[0]]))
+			b.WriteString(code(line[match[0]:match[1]]))
+			last = match[1]
+		}
+		b.WriteString(text(line[last:]))
+		b.WriteString(newline)
+	}
+	return b.String()
+}
+
+// zshPrompt returns lines as a $'...' literal for a zsh prompt, with color
+// codes wrapped in %{ %} so zsh does not count them as printed characters.
+// The escaping assumes the default options, with PROMPT_SUBST off.
+func zshPrompt(lines []string) string {
+	prompt := promptSegments(lines,
+		func(code string) string { return "%{" + code + "%}" },
+		func(text string) string { return strings.ReplaceAll(text, "%", "%%") },
+		"\n")
+	return shellStrings.quote(prompt)
+}
+
+// bashPromptText escapes text for PS1, which bash decodes and then expands
+// like a double-quoted string
+var bashPromptText = strings.NewReplacer(`\`, `\\\\`, `$`, `\\$`, "`", "\\\\`")
+
+// bashPrompt returns lines as a '...' literal for PS1, with color codes
+// wrapped in \[ \] so bash does not count them as printed characters
+func bashPrompt(lines []string) string {
+	prompt := promptSegments(lines,
+		func(code string) string { return `\[` + strings.ReplaceAll(code, "\x1b", `\e`) + `\]` },
+		bashPromptText.Replace,
+		`\n`)
+	return "'" + strings.ReplaceAll(prompt, "'", `'\''`) + "'"
+}
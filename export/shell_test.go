@@ -0,0 +1,80 @@
+// ABOUTME: Tests for the MOTD, shell prompt and terminal greeter exports.
+// ABOUTME: Verifies prompt escape wrapping, printf escaping and the NO_COLOR fallback.
+
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestZshPrompt(t *testing.T) {
+	got := zshPrompt([]string{"\x1b[31m█%\x1b[0m", "'"})
+	expected := `$'%{\x1b[31m%}█%%%{\x1b[0m%}\n\'\n'`
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestBashPrompt(t *testing.T) {
+	got := bashPrompt([]string{"\x1b[31m█$\x1b[0m", "\\'`"})
+	expected := `'\[\e[31m\]█\\$\[\e[0m\]\n\\\\'\''\\` + "`" + `\n'`
+	if got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestPrintfQuoting(t *testing.T) {
+	if got, expected := printfStrings.quote("\x1b[31m%'\\\n"), `'\033[31m%%'\''\\\n'`; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	if got, expected := fishPrintfStrings.quote("\x1b[31m%'\\\n"), `'\x1b[31m%%\'\\\\\n'`; got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestMOTDExport(t *testing.T) {
+	lines := []string{"\x1b[31m█\x1b[0m", "▀"}
+	data, err := Lookup("MOTD").Export(Result{Lines: lines}, DefaultOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "\x1b[31m█\x1b[0m\n▀\n"; string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, data)
+	}
+}
+
+func TestShellExports_NoColorFallback(t *testing.T) {
+	result := Result{Lines: []string{"\x1b[31m█\x1b[0m"}}
+	for _, format := range shellFormats {
+		if format.name == "MOTD" {
+			continue
+		}
+		options := DefaultOptions()
+		plain, err := Lookup(format.name).Export(result, options)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format.name, err)
+		}
+		if strings.Contains(string(plain), "NO_COLOR") {
+			t.Errorf("%s: expected no NO_COLOR check by default", format.name)
+		}
+
+		options.Shell.NoColorFallback = true
+		fallback, err := Lookup(format.name).Export(result, options)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format.name, err)
+		}
+		if !strings.Contains(string(fallback), "NO_COLOR") {
+			t.Errorf("%s: expected a NO_COLOR check, got:\n%s", format.name, fallback)
+		}
+		if strings.Contains(string(fallback), "\x1b") {
+			t.Errorf("%s: expected ESC escaped, got:\n%s", format.name, fallback)
+		}
+	}
+}
+
+func TestLookupExtension_Empty(t *testing.T) {
+	if exporter := LookupExtension(""); exporter != nil {
+		t.Errorf("expected no exporter for an empty extension, got %s", exporter.Name())
+	}
+}
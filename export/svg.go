@@ -0,0 +1,151 @@
+// ABOUTME: SVG generator that converts ANSI-colored text to scalable vector images.
+// ABOUTME: Cells are drawn as for PNG, then runs of same-color pixels merge into one path per color.
+
+package export
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Units of a character cell in the SVG coordinate system. Cells are drawn at
+// this size like PNG cells, so half blocks, shades and frame lines keep their
+// shapes, and the result is scaled to the output size.
+const (
+	svgCellWidth  = 8
+	svgCellHeight = 16
+)
+
+// DefaultSVGPixelSize is the output width of a pixel used when PixelSize is zero
+const DefaultSVGPixelSize = 8
+
+// SVGOptions contains configuration for SVG generation. A pixel is half a
+// character cell: cells are one pixel wide and two pixels tall.
+type SVGOptions struct {
+	PixelSize   float64 // Output width of a pixel (default: DefaultSVGPixelSize)
+	PixelAspect float64 // Height to width ratio of a pixel (default: 1, square pixels as in a terminal)
+	Background  string  // Hex background color (empty keeps the background transparent)
+
+	// FillChars maps custom fill characters back to the block or shade
+	// character they replace, as in PNGOptions
+	FillChars map[rune]rune
+}
+
+// DefaultSVGOptions returns default SVG generation options: square 8x8 pixels
+// on a transparent background
+func DefaultSVGOptions() SVGOptions {
+	return SVGOptions{
+		PixelSize:   DefaultSVGPixelSize,
+		PixelAspect: 1,
+	}
+}
+
+// svgRect is a run of same-color pixels, merged with identical runs on the rows below
+type svgRect struct {
+	x, y, width, height int
+	color               color.RGBA
+}
+
+// GenerateSVG creates an SVG image from rendered ANSI lines.
+// Returns the SVG document or error.
+func GenerateSVG(lines []string, options SVGOptions) (string, error) {
+	if len(lines) == 0 {
+		return "", fmt.Errorf("no content to export")
+	}
+
+	// Use defaults if zero values provided
+	if options.PixelSize <= 0 {
+		options.PixelSize = DefaultSVGPixelSize
+	}
+	if options.PixelAspect <= 0 {
+		options.PixelAspect = 1
+	}
+
+	var background color.RGBA
+	if options.Background != "" {
+		var err error
+		if background, err = parseHexColor(options.Background); err != nil {
+			return "", err
+		}
+	}
+
+	cellOptions := PNGOptions{CellWidth: svgCellWidth, CellHeight: svgCellHeight, FillChars: options.FillChars}
+	img := rasterizeLines(lines, cellOptions, background)
+	bounds := img.Bounds()
+	columns, rows := bounds.Dx()/svgCellWidth, bounds.Dy()/svgCellHeight
+
+	width := float64(columns) * options.PixelSize
+	height := float64(rows) * 2 * options.PixelSize * options.PixelAspect
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %d %d" preserveAspectRatio="none" shape-rendering="crispEdges">`+"\n",
+		formatSVGNumber(width), formatSVGNumber(height), bounds.Dx(), bounds.Dy())
+	if options.Background != "" {
+		fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`+"\n", bounds.Dx(), bounds.Dy(), hexColor(background))
+	}
+
+	// One path per color, in the order the colors first appear
+	rects := mergePixelRuns(img)
+	var colors []color.RGBA
+	paths := make(map[color.RGBA]*strings.Builder)
+	for _, rect := range rects {
+		path, ok := paths[rect.color]
+		if !ok {
+			path = &strings.Builder{}
+			paths[rect.color] = path
+			colors = append(colors, rect.color)
+		}
+		fmt.Fprintf(path, "M%d %dh%dv%dh-%dz", rect.x, rect.y, rect.width, rect.height, rect.width)
+	}
+	for _, c := range colors {
+		fmt.Fprintf(&b, `<path fill="%s" d="%s"/>`+"\n", hexColor(c), paths[c].String())
+	}
+	b.WriteString("</svg>\n")
+
+	return b.String(), nil
+}
+
+// mergePixelRuns collects the runs of same-color opaque pixels on every row
+// and merges runs that continue a run of the same extent and color on the row above
+func mergePixelRuns(img *image.RGBA) []svgRect {
+	type runKey struct {
+		x, width int
+		color    color.RGBA
+	}
+
+	var rects []svgRect
+	open := make(map[runKey]int) // Index in rects of the runs ending on the previous row
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		continued := make(map[runKey]int)
+		for x := bounds.Min.X; x < bounds.Max.X; {
+			c := img.RGBAAt(x, y)
+			start := x
+			for x < bounds.Max.X && img.RGBAAt(x, y) == c {
+				x++
+			}
+			if c.A == 0 {
+				continue
+			}
+
+			key := runKey{x: start, width: x - start, color: c}
+			if index, ok := open[key]; ok {
+				rects[index].height++
+				continued[key] = index
+				continue
+			}
+			rects = append(rects, svgRect{x: start, y: y, width: x - start, height: 1, color: c})
+			continued[key] = len(rects) - 1
+		}
+		open = continued
+	}
+	return rects
+}
+
+// formatSVGNumber formats a length without trailing zeros
+func formatSVGNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,120 @@
+// ABOUTME: Tests for SVG generation from ANSI-colored text output.
+// ABOUTME: Verifies sizing, half-block shapes, pixel run merging and backgrounds.
+
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateSVG_EmptyInput(t *testing.T) {
+	_, err := GenerateSVG([]string{}, DefaultSVGOptions())
+	if err == nil {
+		t.Error("expected error for empty input, got nil")
+	}
+}
+
+func TestGenerateSVG_Dimensions(t *testing.T) {
+	// Two cells wide, one cell tall: 2x2 pixels of 8 output pixels each
+	lines := []string{"\x1b[38;2;255;0;0m██\x1b[0m"}
+
+	svg, err := GenerateSVG(lines, DefaultSVGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(svg, `width="16" height="16" viewBox="0 0 16 16"`) {
+		t.Errorf("unexpected SVG size in %q", svg)
+	}
+}
+
+func TestGenerateSVG_PixelAspect(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m█\x1b[0m"}
+	options := SVGOptions{PixelSize: 4, PixelAspect: 1.5}
+
+	svg, err := GenerateSVG(lines, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// One cell is one pixel wide and two pixels tall
+	if !strings.Contains(svg, `width="4" height="12"`) {
+		t.Errorf("expected a 4x12 image, got %q", svg)
+	}
+}
+
+func TestGenerateSVG_MergesAdjacentPixels(t *testing.T) {
+	// Three blocks of one color become a single rectangle in a single path
+	lines := []string{"\x1b[38;2;255;0;0m███\x1b[0m", "\x1b[38;2;255;0;0m███\x1b[0m"}
+
+	svg, err := GenerateSVG(lines, DefaultSVGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if count := strings.Count(svg, "<path"); count != 1 {
+		t.Errorf("expected 1 path, got %d", count)
+	}
+	if !strings.Contains(svg, `<path fill="#ff0000" d="M0 0h24v32h-24z"/>`) {
+		t.Errorf("expected one merged rectangle, got %q", svg)
+	}
+}
+
+func TestGenerateSVG_UpperHalfBlock(t *testing.T) {
+	lines := []string{"\x1b[38;2;0;255;0m▀\x1b[0m"}
+
+	svg, err := GenerateSVG(lines, DefaultSVGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the top half of the cell is filled
+	if !strings.Contains(svg, `d="M0 0h8v8h-8z"`) {
+		t.Errorf("expected the top half filled, got %q", svg)
+	}
+}
+
+func TestGenerateSVG_OnePathPerColor(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;0;0m█\x1b[0m\x1b[38;2;0;0;255m█\x1b[0m\x1b[38;2;255;0;0m█\x1b[0m"}
+
+	svg, err := GenerateSVG(lines, DefaultSVGOptions())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if count := strings.Count(svg, "<path"); count != 2 {
+		t.Errorf("expected 2 paths, got %d", count)
+	}
+	if !strings.Contains(svg, `<path fill="#ff0000" d="M0 0h8v16h-8zM16 0h8v16h-8z"/>`) {
+		t.Errorf("expected both red cells in one path, got %q", svg)
+	}
+}
+
+func TestGenerateSVG_Background(t *testing.T) {
+	lines := []string{"\x1b[38;2;255;255;255m░\x1b[0m"}
+	options := DefaultSVGOptions()
+	options.Background = "#0000FF"
+
+	svg, err := GenerateSVG(lines, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(svg, `<rect width="8" height="16" fill="#0000ff"/>`) {
+		t.Errorf("expected a background rectangle, got %q", svg)
+	}
+	// The light shade blends a quarter of the way from the background to white
+	if !strings.Contains(svg, `fill="#3f3fff"`) {
+		t.Errorf("expected the shade blended toward the background, got %q", svg)
+	}
+}
+
+func TestGenerateSVG_InvalidBackground(t *testing.T) {
+	options := DefaultSVGOptions()
+	options.Background = "blue"
+
+	if _, err := GenerateSVG([]string{"█"}, options); err == nil {
+		t.Error("expected error for invalid background color, got nil")
+	}
+}
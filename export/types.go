@@ -0,0 +1,22 @@
+package export
+
+// ABOUTME: Defines the export format descriptor the export manager lists formats with.
+// ABOUTME: Descriptors are taken from the exporters in the registry.
+
+// ExportFormat represents a supported export format
+type ExportFormat struct {
+	Name        string // Canonical key (e.g., "TXT", "PNG")
+	Extension   string
+	Description string
+	IsBinary    bool // True for binary formats like PNG that need []byte handling
+}
+
+// formatOf returns the descriptor of an exporter
+func formatOf(exporter Exporter) ExportFormat {
+	return ExportFormat{
+		Name:        exporter.Name(),
+		Extension:   exporter.Extension(),
+		Description: exporter.Description(),
+		IsBinary:    exporter.IsBinary(),
+	}
+}
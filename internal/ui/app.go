@@ -4,6 +4,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -11,7 +12,8 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/paulilaaso/bit/internal/export"
+	"github.com/paulilaaso/bit/ansifonts"
+	"github.com/paulilaaso/bit/export"
 )
 
 func InitialModel() (model, error) {
@@ -45,6 +47,13 @@ func InitialModel() (model, error) {
 	filenameInput.TextStyle = filenameInputTextStyle
 	filenameInput.PlaceholderStyle = filenameInputPlaceholderStyle
 
+	// Initialize the inputs of the Go and ANS options steps
+	goPackageInput := newOptionInput("main program")
+	goNameInput := newOptionInput(export.DefaultGoIdentifier)
+	goNameInput.SetValue(export.DefaultGoIdentifier)
+	ansAuthorInput := newOptionInput("font's author")
+	ansAuthorInput.CharLimit = ANSAuthorCharLimit
+
 	// Initialize export manager
 	exportManager := export.NewExportManager()
 
@@ -109,6 +118,9 @@ func InitialModel() (model, error) {
 			showConfirmation: false,         // Start with export confirmation hidden
 			confirmationText: "",            // No confirmation text initially
 			manager:          exportManager, // Store export manager in model
+			goPackageInput:   goPackageInput,
+			goNameInput:      goNameInput,
+			ansAuthorInput:   ansAuthorInput,
 		},
 		uiState: uiStateModel{
 			focusedPanel:  TextInputPanel, // Start with text input panel
@@ -270,6 +282,8 @@ func (m *model) handleTabKey() tea.Cmd {
 		m.spacing.mode = SpacingMode((int(m.spacing.mode) + 1) % int(TotalSpacingModes))
 	case ColorPanel:
 		m.color.subMode = ColorSubMode((int(m.color.subMode) + 1) % int(TotalColorSubModes))
+	case ScalePanel:
+		m.scale.subMode = ScaleSubMode((int(m.scale.subMode) + 1) % int(TotalScaleSubModes))
 	case ShadowPanel:
 		m.shadow.subMode = ShadowSubMode((int(m.shadow.subMode) + 1) % int(TotalShadowSubModes))
 	default:
@@ -328,32 +342,48 @@ func (m *model) exportText() {
 	}
 
 	formatName := m.export.format
+	exporter := export.Lookup(formatName)
+	if exporter == nil {
+		m.export.showConfirmation = true
+		m.export.confirmationText = fmt.Sprintf("Unknown format: %s", formatName)
+		return
+	}
 
-	// Check if this is a binary format (like PNG)
-	if m.export.manager.IsBinaryFormat(formatName) {
-		m.exportBinaryFormat(sanitizedFilename, formatName)
+	result := export.Result{
+		Lines: m.uiState.renderedLines,
+		Text:  m.textInput.currentText,
+		Font:  m.uiState.renderedFont,
+	}
+	if exporter.Animated() {
+		// Only animated exports need the frames, which take a while to render
+		result.Frames = m.animationFrames()
+	}
+	options := export.DefaultOptions()
+	options.PNG = m.pngOptions()
+	options.Go = m.goOptions()
+	options.ANS.Author = strings.TrimSpace(m.export.ansAuthorInput.Value())
+	options.HTML.Fragment = m.export.htmlFragment
+	options.Shell.NoColorFallback = m.export.noColorFallback
+	data, err := exporter.Export(result, options)
+	m.export.warning = ""
+	var warning *export.Warning
+	if errors.As(err, &warning) {
+		// The export is usable; tell the user what was changed with its confirmation
+		m.export.warning = warning.Message
+		err = nil
+	}
+	if err != nil {
+		m.export.showConfirmation = true
+		m.export.confirmationText = fmt.Sprintf("%s generation failed: %v", exporter.Name(), err)
 		return
 	}
 
-	// Generate content based on selected format (text formats)
-	var content string
-	switch m.export.format {
-	case "TXT":
-		content = export.GenerateTXTCode(m.uiState.renderedLines)
-	case "GO":
-		content = export.GenerateGoCode(m.uiState.renderedLines)
-	case "JS":
-		content = export.GenerateJSCode(m.uiState.renderedLines)
-	case "PY":
-		content = export.GeneratePythonCode(m.uiState.renderedLines)
-	case "RS":
-		content = export.GenerateRustCode(m.uiState.renderedLines)
-	case "SH":
-		content = export.GenerateBashCode(m.uiState.renderedLines)
-	default:
-		// Default to TXT if format not recognized
-		content = export.GenerateTXTCode(m.uiState.renderedLines)
+	// Binary formats (like PNG) are written as bytes
+	if exporter.IsBinary() {
+		m.exportBinaryFormat(data, sanitizedFilename, formatName)
+		return
 	}
+	content := string(data)
 
 	// Check if file exists before attempting export
 	exists, finalFilename, err := m.export.manager.CheckFileExists(sanitizedFilename, formatName)
@@ -377,26 +407,8 @@ func (m *model) exportText() {
 	m.performExport(content, sanitizedFilename, formatName)
 }
 
-// exportBinaryFormat handles export of binary formats like PNG
-func (m *model) exportBinaryFormat(filename, formatName string) {
-	var content []byte
-	var err error
-
-	switch formatName {
-	case "PNG":
-		options := export.TerminalAspectRatioPNGOptions()
-		content, err = export.GeneratePNG(m.uiState.renderedLines, options)
-		if err != nil {
-			m.export.showConfirmation = true
-			m.export.confirmationText = fmt.Sprintf("PNG generation failed: %v", err)
-			return
-		}
-	default:
-		m.export.showConfirmation = true
-		m.export.confirmationText = fmt.Sprintf("Unknown binary format: %s", formatName)
-		return
-	}
-
+// exportBinaryFormat handles export of binary formats like PNG, GIF and ANS
+func (m *model) exportBinaryFormat(content []byte, filename, formatName string) {
 	// Check if file exists before attempting export
 	exists, finalFilename, err := m.export.manager.CheckFileExists(filename, formatName)
 	if err != nil {
@@ -420,6 +432,58 @@ func (m *model) exportBinaryFormat(filename, formatName string) {
 	m.performBinaryExport(content, filename, formatName)
 }
 
+// animationFrames renders the frames of a GIF export with the options of the
+// preview: the gradient turns when a gradient is on, the shadow circles the
+// text when a shadow is on, and otherwise the text is typed out
+func (m *model) animationFrames() [][]string {
+	if m.uiState.renderedFont == nil {
+		return nil
+	}
+
+	options := m.uiState.renderedOptions
+	animation := ansifonts.TypewriterReveal
+	if options.UseGradient {
+		animation = ansifonts.GradientRotation
+	} else if options.ShadowEnabled {
+		animation = ansifonts.ShadowSweep
+	}
+	return ansifonts.RenderAnimation(m.textInput.currentText, m.uiState.renderedFont, options, animation, 0)
+}
+
+// pngOptions returns the PNG options chosen in the PNG options step
+func (m *model) pngOptions() export.PNGOptions {
+	cells := pngCellOptions[m.export.pngCellIndex]
+	return export.PNGOptions{
+		CellWidth:    cells.CellWidth,
+		CellHeight:   cells.CellHeight,
+		PixelSize:    cells.PixelSize,
+		Scale:        pngScaleOptions[m.export.pngScaleIndex],
+		Background:   pngBackgroundOptions[m.export.pngBackgroundIdx].Hex,
+		Padding:      pngPaddingOptions[m.export.pngPaddingIndex],
+		DitherShades: m.export.pngDither,
+	}
+}
+
+// goOptions returns the package and identifier chosen in the Go options step
+func (m *model) goOptions() export.GoOptions {
+	return export.GoOptions{
+		Package:    strings.TrimSpace(m.export.goPackageInput.Value()),
+		Identifier: strings.TrimSpace(m.export.goNameInput.Value()),
+	}
+}
+
+// newOptionInput creates a text input of an export options step
+func newOptionInput(placeholder string) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.CharLimit = FilenameInputCharLimit
+	input.Width = FilenameInputWidth / 2
+	input.ShowSuggestions = false
+	input.TextStyle = filenameInputTextStyle
+	input.PlaceholderStyle = filenameInputPlaceholderStyle
+	return input
+}
+
 // performBinaryExport writes binary content to file
 func (m *model) performBinaryExport(content []byte, filename, formatName string) {
 	err := m.export.manager.ExportBinary(content, filename, formatName)
@@ -438,6 +502,9 @@ func (m *model) performBinaryExport(content []byte, filename, formatName string)
 	}
 	m.export.showConfirmation = true
 	m.export.confirmationText = fmt.Sprintf("Exported to %s/%s", cwd, sanitizedFilename)
+	if m.export.warning != "" {
+		m.export.confirmationText += " • Warning: " + m.export.warning
+	}
 }
 
 // performExport actually writes the file
@@ -459,6 +526,9 @@ func (m *model) performExport(content, filename, formatName string) {
 	}
 	m.export.showConfirmation = true
 	m.export.confirmationText = fmt.Sprintf("Exported to %s/%s", cwd, sanitizedFilename)
+	if m.export.warning != "" {
+		m.export.confirmationText += " • Warning: " + m.export.warning
+	}
 }
 
 // getFormatDescription returns the description for a given export format
@@ -477,20 +547,7 @@ func (m model) View() string {
 		return m.renderExportView()
 	}
 
-	// Calculate heights for different sections
-	controlPanelsHeight := 3
-	if m.uiState.usesTwoRows {
-		controlPanelsHeight = 8
-	}
-
-	controlsHeight := 1
-	titleHeight := 1
-	minRequiredHeight := titleHeight + controlPanelsHeight + controlsHeight + 2
-
-	// Calculate available space for text display
-	availableForText := m.uiState.height - minRequiredHeight
-	minTextHeight := 3
-	mainDisplayHeight := max(availableForText, minTextHeight)
+	mainDisplayHeight := m.mainDisplayHeight()
 
 	// Render each section
 	centeredTitle := m.renderTitleView()
@@ -512,6 +569,25 @@ func (m model) View() string {
 		Render(content)
 }
 
+// mainDisplayHeight returns the height of the text display, which takes the
+// space left over by the title, control panels and controls
+func (m model) mainDisplayHeight() int {
+	// Calculate heights for different sections
+	controlPanelsHeight := 3
+	if m.uiState.usesTwoRows {
+		controlPanelsHeight = 8
+	}
+
+	controlsHeight := 1
+	titleHeight := 1
+	minRequiredHeight := titleHeight + controlPanelsHeight + controlsHeight + 2
+
+	// Calculate available space for text display
+	availableForText := m.uiState.height - minRequiredHeight
+	minTextHeight := 3
+	return max(availableForText, minTextHeight)
+}
+
 func isUpKey(txt string) bool {
 	return txt == "up" || txt == "k"
 }
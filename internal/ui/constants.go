@@ -30,6 +30,7 @@ const (
 	TextEntryMode TextInputMode = iota
 	TextKerningMode
 	TextAlignmentMode
+	TextRowStyleMode
 	TotalTextInputModes
 )
 
@@ -40,6 +41,7 @@ const (
 	TextColorMode ColorSubMode = iota
 	GradientColorMode
 	GradientDirectionMode
+	PatternMode
 	TotalColorSubModes
 )
 
@@ -50,9 +52,43 @@ const (
 	HorizontalShadowMode ShadowSubMode = iota
 	VerticalShadowMode
 	ShadowStyleMode
+	GlowMode
+	ExtrusionMode
+	ReflectionMode
 	TotalShadowSubModes
 )
 
+// Rows of the PNG options step of the export view
+type PNGOptionRow int
+
+const (
+	PNGCellsRow PNGOptionRow = iota
+	PNGScaleRow
+	PNGBackgroundRow
+	PNGPaddingRow
+	PNGShadesRow
+	TotalPNGOptionRows
+)
+
+// Rows of the Go options step of the export view
+type GoOptionRow int
+
+const (
+	GoPackageRow GoOptionRow = iota
+	GoIdentifierRow
+	TotalGoOptionRows
+)
+
+// Scale sub-modes for the scale panel
+type ScaleSubMode int
+
+const (
+	TextScaleMode ScaleSubMode = iota
+	SyntheticBoldMode
+	SyntheticObliqueMode
+	TotalScaleSubModes
+)
+
 // Text alignment options
 type TextAlignment int
 
@@ -71,8 +107,9 @@ const (
 	ScaleOne  TextScale = 0  // 1x
 	ScaleTwo  TextScale = 1  // 2x
 	ScaleFour TextScale = 2  // 4x
+	ScaleFit  TextScale = 3  // Largest scale that fits the preview pane
 	MinScale            = ScaleHalf
-	MaxScale            = ScaleFour
+	MaxScale            = ScaleFit
 )
 
 // Gradient direction indices
@@ -100,6 +137,12 @@ const (
 	MaxVerticalShadowPixels = 5
 )
 
+// Synthetic style range constants
+const (
+	MinBoldStrength = 0
+	MaxBoldStrength = 3
+)
+
 // Spacing range constants
 const (
 	MinCharSpacing = 0
@@ -126,6 +169,7 @@ const (
 	FilenameInputCharLimit = 50
 	FilenameInputWidth     = 40
 	MaxFilenameLength      = 200 // Maximum filename length before extension
+	ANSAuthorCharLimit     = 20  // Width of the SAUCE author field
 )
 
 // Layout thresholds
@@ -7,6 +7,8 @@ import (
 )
 
 func (m *model) renderText() {
+	m.uiState.metrics = ansifonts.TextMetrics{}
+	m.uiState.renderedFont = nil
 	if len(m.font.fonts) == 0 || m.textInput.currentText == "" {
 		m.uiState.renderedLines = []string{"No text or fonts available"}
 		return
@@ -42,24 +44,113 @@ func (m *model) renderText() {
 		GradientColor:          colorOptions[m.color.gradientColor].Hex,
 		GradientDirection:      ansifonts.GradientDirection(m.color.gradientDirection),
 		UseGradient:            m.color.gradientEnabled && m.color.gradientColor != m.color.textColor,
+		PaletteMode:            m.color.paletteMode,
+		PaletteOrder:           ansifonts.PaletteRandom,
+		Palette:                randomPaletteColors(),
+		PaletteSeed:            m.color.paletteSeed,
+		Pattern:                patternOptions[m.color.patternIndex].Pattern,
+		PatternSize:            patternOptions[m.color.patternIndex].Size,
 		ScaleFactor:            m.getScaleFactorFloat(),
+		BoldStrength:           m.scale.boldStrength,
+		ObliqueShear:           obliqueOptions[m.scale.obliqueIndex].Shear,
 		ShadowEnabled:          m.shadow.enabled,
 		ShadowHorizontalOffset: m.shadow.horizontalOffset,
 		ShadowVerticalOffset:   m.shadow.verticalOffset,
 		ShadowStyle:            ansifonts.ShadowStyle(m.shadow.style),
+		GlowEnabled:            glowOptions[m.shadow.glowIndex].Radius > 0,
+		GlowRadius:             glowOptions[m.shadow.glowIndex].Radius,
+		ExtrusionEnabled:       extrusionOptions[m.shadow.extrusionIndex].Enabled,
+		LongShadow:             extrusionOptions[m.shadow.extrusionIndex].Long,
+		ReflectionEnabled:      reflectionOptions[m.shadow.reflectionIndex].Enabled,
+		ReflectionFade:         reflectionOptions[m.shadow.reflectionIndex].Fade,
+		ReflectionHeight:       reflectionOptions[m.shadow.reflectionIndex].Height,
 		CustomKerning:          m.textInput.customKerning,
+		LineStyles:             m.rowLineStyles(),
 	}
 
-	// Check for half-pixel usage to show warning in UI
-	// The ansifonts library will automatically disable shadows if needed
-	hasHalfPixels := ansifonts.DetectHalfPixelUsage(m.textInput.currentText, ansiFontData, m.getScaleFactorFloat())
-	m.shadow.showWarning = hasHalfPixels && m.shadow.enabled && (m.shadow.horizontalOffset != 0 || m.shadow.verticalOffset != 0)
-
 	// Clear previous rendered lines to prevent memory leak
 	m.uiState.renderedLines = nil
 
 	// Render using the ansifonts library - all rendering logic is centralized there
-	m.uiState.renderedLines = ansifonts.RenderTextWithFont(m.textInput.currentText, ansiFontData, options)
+	if m.scale.scale == ScaleFit {
+		options = m.renderFittedText(ansiFontData, options)
+	} else {
+		m.uiState.renderedLines = ansifonts.RenderTextWithFont(m.textInput.currentText, ansiFontData, options)
+	}
+	m.uiState.renderedFont = &ansifonts.Font{Name: ansiFontData.Name, FontData: ansiFontData}
+	m.uiState.renderedOptions = options
+
+	// Check for half-pixel usage to show warning in UI
+	// The ansifonts library will automatically disable shadows if needed
+	hasHalfPixels := ansifonts.DetectHalfPixelUsageWithOptions(m.textInput.currentText, ansiFontData, options)
+	m.shadow.showWarning = hasHalfPixels && m.hasShadowLayers()
+
+	// Measure the layout for the kerning cursor marker
+	if m.textInput.mode == TextKerningMode {
+		m.uiState.metrics = ansifonts.MeasureText(m.textInput.currentText, m.uiState.renderedFont, options)
+	}
+}
+
+// renderFittedText renders the text at the largest scale that fits the preview
+// pane, keeping the spacing and line breaks, and returns the options it used.
+// Text too large for the pane at any scale is rendered at 0.5x and clipped.
+func (m *model) renderFittedText(fontData ansifonts.FontData, options ansifonts.RenderOptions) ansifonts.RenderOptions {
+	fit := ansifonts.FitOptions{
+		Width:        m.uiState.width - 4,
+		Height:       m.mainDisplayHeight() - 1,
+		FixedSpacing: true,
+	}
+	font := &ansifonts.Font{Name: fontData.Name, FontData: fontData}
+	result, err := ansifonts.FitText(m.textInput.currentText, font, options, fit)
+	if err != nil {
+		options.ScaleFactor = 0.5
+		m.uiState.renderedLines = ansifonts.RenderTextWithFont(m.textInput.currentText, fontData, options)
+		return options
+	}
+	m.uiState.renderedLines = result.Lines
+	return result.Options
+}
+
+// rowLineStyles converts the row style presets of the text panel into line styles
+func (m *model) rowLineStyles() map[int]ansifonts.LineStyle {
+	if len(m.textInput.rowStyles) == 0 {
+		return nil
+	}
+	lineStyles := make(map[int]ansifonts.LineStyle)
+	for row, style := range m.textInput.rowStyles {
+		option := rowStyleOptions[style.option]
+		lineStyle := ansifonts.LineStyle{ScaleFactor: option.Scale}
+		if option.PinFont && style.fontIndex < len(m.font.fonts) {
+			pinnedFont := &m.font.fonts[style.fontIndex]
+			if !pinnedFont.Loaded {
+				if err := loadFontData(pinnedFont); err != nil {
+					continue
+				}
+			}
+			lineStyle.Font = &ansifonts.Font{
+				Name: pinnedFont.FontData.Name,
+				FontData: ansifonts.FontData{
+					Name:       pinnedFont.FontData.Name,
+					Author:     pinnedFont.FontData.Author,
+					License:    pinnedFont.FontData.License,
+					Characters: pinnedFont.FontData.Characters,
+				},
+			}
+		}
+		lineStyles[row] = lineStyle
+	}
+	return lineStyles
+}
+
+// randomPaletteColors returns the colors that per-letter and per-word random palettes draw from
+func randomPaletteColors() []string {
+	var palette []string
+	for _, c := range colorOptions {
+		if !c.ExcludeFromRandom {
+			palette = append(palette, c.Hex)
+		}
+	}
+	return palette
 }
 
 // getScaleFactorFloat converts the UI scale enum to a float64 scale factor
@@ -73,6 +164,9 @@ func (m *model) getScaleFactorFloat() float64 {
 		return 2.0
 	case ScaleFour:
 		return 4.0
+	case ScaleFit:
+		// renderText picks the scale that fits the preview pane
+		return 1.0
 	default:
 		return 1.0
 	}
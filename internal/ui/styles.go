@@ -2,6 +2,7 @@ package ui
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/paulilaaso/bit/ansifonts"
 )
 
 // colorOptions references the centralized color palette
@@ -55,6 +56,128 @@ var shadowStyleOptions = []ShadowStyleOption{
 	{"Dark Shade", '▓', ""},   // U+2593 DARK SHADE - Uses main text color
 }
 
+// Synthetic oblique options, as shear in columns per pixel row
+type ObliqueOption struct {
+	Name  string
+	Shear float64
+}
+
+var obliqueOptions = []ObliqueOption{
+	{"Off", 0},
+	{"Slight", 0.25},
+	{"Medium", 0.5},
+	{"Strong", 0.75},
+	{"Extreme", 1.0},
+}
+
+// Glow presets for the shadow panel
+type GlowOption struct {
+	Name   string
+	Radius int // Glow reach in pixels, 0 disables the glow
+}
+
+var glowOptions = []GlowOption{
+	{"Off", 0},
+	{"Soft Glow", 1},
+	{"Neon Glow", 3},
+	{"Wide Glow", 6},
+}
+
+// Extrusion presets for the shadow panel; layers follow the shadow offsets
+type ExtrusionOption struct {
+	Name    string
+	Enabled bool
+	Long    bool
+}
+
+var extrusionOptions = []ExtrusionOption{
+	{"Off", false, false},
+	{"3D Block", true, false},
+	{"Long Shadow", true, true},
+}
+
+// Reflection presets for the shadow panel
+type ReflectionOption struct {
+	Name    string
+	Enabled bool
+	Fade    ansifonts.ReflectionFade
+	Height  int // Percentage of the text height
+}
+
+var reflectionOptions = []ReflectionOption{
+	{"Off", false, ansifonts.FadeShade, 0},
+	{"▓▒░ Fade", true, ansifonts.FadeShade, 100},
+	{"▓▒░ Fade Half", true, ansifonts.FadeShade, 50},
+	{"Color Fade", true, ansifonts.FadeColor, 100},
+	{"Color Fade Half", true, ansifonts.FadeColor, 50},
+	{"▓▒░ + Color Fade", true, ansifonts.FadeShadeColor, 100},
+}
+
+// Cell size presets for PNG export
+type PNGCellOption struct {
+	Name       string
+	CellWidth  int
+	CellHeight int
+	PixelSize  int // Pixel-perfect square pixels, overriding the cell size when set
+}
+
+var pngCellOptions = []PNGCellOption{
+	{"Terminal 16×32", 16, 32, 0},
+	{"Square 16×16", 16, 16, 0},
+	{"Pixel-perfect", 0, 0, 1},
+}
+
+// Output scale and padding presets for PNG export
+var pngScaleOptions = []int{1, 2, 3, 4, 8}
+var pngPaddingOptions = []int{0, 8, 16, 32}
+
+// pngBackgroundOptions lists the PNG backgrounds, transparent first
+var pngBackgroundOptions = append([]ColorOption{{Name: "Transparent"}, {Name: "Black", Hex: "#000000"}}, colorOptions...)
+
+// noColorFallbackFormats are the export formats that can add a plain
+// variant for NO_COLOR, which ask whether to after the filename
+var noColorFallbackFormats = map[string]bool{
+	"UPDATEMOTD": true,
+	"ZSH":        true,
+	"BASH":       true,
+	"FISH":       true,
+	"PSPROFILE":  true,
+}
+
+// Row style presets for the text panel. A pinned font keeps the row in the
+// font that was selected when the preset was first chosen.
+type RowStyleOption struct {
+	Name    string
+	Scale   float64 // Scale factor of the row (0 keeps the block scale)
+	PinFont bool
+}
+
+var rowStyleOptions = []RowStyleOption{
+	{"Block Style", 0, false},
+	{"Headline 2x", 2.0, false},
+	{"Tagline 0.5x", 0.5, false},
+	{"Pinned Font", 0, true},
+	{"Pinned Font 2x", 2.0, true},
+	{"Pinned Font 0.5x", 0.5, true},
+}
+
+// Fill pattern presets for the color panel
+type PatternOption struct {
+	Name    string
+	Pattern ansifonts.FillPattern
+	Size    int // Pattern cell size in pixels
+}
+
+var patternOptions = []PatternOption{
+	{"None", ansifonts.NoPattern, 0},
+	{"Stripes", ansifonts.StripePattern, 1},
+	{"Wide Stripes", ansifonts.StripePattern, 2},
+	{"Checkerboard", ansifonts.CheckerPattern, 1},
+	{"Diagonal Hatch", ansifonts.HatchPattern, 1},
+	{"Ordered Dither", ansifonts.DitherPattern, 1},
+	{"CRT Scanlines", ansifonts.ScanlinePattern, 1},
+}
+
 // Gradient direction options
 var gradientDirectionOptions = []GradientDirectionOption{
 	{"Up-Down"},
@@ -5,7 +5,8 @@ package ui
 
 import (
 	"github.com/charmbracelet/bubbles/textinput"
-	"github.com/paulilaaso/bit/internal/export"
+	"github.com/paulilaaso/bit/ansifonts"
+	"github.com/paulilaaso/bit/export"
 )
 
 // textInputModel handles text entry and alignment
@@ -17,9 +18,16 @@ type textInputModel struct {
 	customKerning map[int]map[int]int // Per-row, per-cursor manual spacing adjustments
 	currentRow    int                 // Currently selected row for editing
 	alignment     TextAlignment       // Text alignment
+	rowStyles     map[int]rowStyle    // Per-row style overrides
 	mode          TextInputMode       // Text input panel sub-mode
 }
 
+// rowStyle attaches a row style preset to one text row
+type rowStyle struct {
+	option    int // Index into rowStyleOptions
+	fontIndex int // Pinned font index into fontModel.fonts
+}
+
 // fontModel handles font selection
 type fontModel struct {
 	fonts        []FontInfo
@@ -36,16 +44,22 @@ type spacingModel struct {
 
 // colorModel handles text color and gradient settings
 type colorModel struct {
-	textColor         int               // Index into colorOptions array
-	gradientColor     int               // Index into colorOptions array for gradient end color
-	gradientEnabled   bool              // Whether gradient is enabled
-	gradientDirection GradientDirection // Gradient direction
-	subMode           ColorSubMode      // Color panel sub-mode
+	textColor         int                   // Index into colorOptions array
+	gradientColor     int                   // Index into colorOptions array for gradient end color
+	gradientEnabled   bool                  // Whether gradient is enabled
+	gradientDirection GradientDirection     // Gradient direction
+	patternIndex      int                   // Index into patternOptions array
+	paletteMode       ansifonts.PaletteMode // Per-letter or per-word random colors set by the randomizer
+	paletteSeed       uint64                // Seed that keeps the random palette colors stable
+	subMode           ColorSubMode          // Color panel sub-mode
 }
 
 // scaleModel handles text scaling
 type scaleModel struct {
-	scale TextScale // Text scaling factor
+	scale        TextScale    // Text scaling factor
+	boldStrength int          // Synthetic bold strength in pixels
+	obliqueIndex int          // Index into obliqueOptions array
+	subMode      ScaleSubMode // Scale panel sub-mode
 }
 
 // shadowModel handles shadow settings
@@ -56,6 +70,9 @@ type shadowModel struct {
 	horizontalIndex  int           // Index into shadowPixelOptions array (UI mapping)
 	verticalIndex    int           // Index into verticalShadowPixelOptions array (UI mapping)
 	style            int           // Index into shadowStyleOptions array (ANSI block styles)
+	glowIndex        int           // Index into glowOptions array
+	extrusionIndex   int           // Index into extrusionOptions array
+	reflectionIndex  int           // Index into reflectionOptions array
 	showWarning      bool          // Whether to show the shadow warning message
 	subMode          ShadowSubMode // Shadow panel sub-mode
 }
@@ -67,6 +84,7 @@ type exportModel struct {
 	filenameInput          textinput.Model       // Text input for filename
 	showConfirmation       bool                  // Whether to show export confirmation in header
 	confirmationText       string                // The confirmation text to display
+	warning                string                // Warning of the last export, shown with its confirmation
 	showOverwritePrompt    bool                  // Whether to show overwrite confirmation
 	overwriteFilename      string                // Filename that would be overwritten
 	overwriteContent       string                // Content to write if user confirms (text formats)
@@ -74,6 +92,33 @@ type exportModel struct {
 	overwriteFormat        string                // Format for the overwrite
 	selectedButton         int                   // 0 = Yes, 1 = No
 	manager                *export.ExportManager // Export manager for format information
+
+	// PNG options step, shown after the filename for PNG exports
+	showPNGOptions   bool         // Whether the PNG options step is shown
+	pngOptionRow     PNGOptionRow // Focused row of the PNG options step
+	pngCellIndex     int          // Index into pngCellOptions
+	pngScaleIndex    int          // Index into pngScaleOptions
+	pngBackgroundIdx int          // Index into pngBackgroundOptions
+	pngPaddingIndex  int          // Index into pngPaddingOptions
+	pngDither        bool         // Draw shades as dither patterns
+
+	// Go options step, shown after the filename for Go exports
+	showGoOptions  bool            // Whether the Go options step is shown
+	goOptionRow    GoOptionRow     // Focused row of the Go options step
+	goPackageInput textinput.Model // Package name, empty for a main program
+	goNameInput    textinput.Model // Name of the banner constant in a package
+
+	// ANS options step, shown after the filename for ANS exports
+	showANSOptions bool            // Whether the ANS options step is shown
+	ansAuthorInput textinput.Model // SAUCE author, empty for the font's author
+
+	// HTML options step, shown after the filename for HTML exports
+	showHTMLOptions bool // Whether the HTML options step is shown
+	htmlFragment    bool // Write only the <pre> block, for embedding
+
+	// NO_COLOR step, shown after the filename for prompt and greeter exports
+	showShellOptions bool // Whether the NO_COLOR step is shown
+	noColorFallback  bool // Add a plain variant used when NO_COLOR is set
 }
 
 // uiStateModel handles general UI state
@@ -81,8 +126,12 @@ type uiStateModel struct {
 	focusedPanel  FocusedPanel // Currently focused panel
 	width         int
 	height        int
-	renderedLines []string // Rendered text cache
-	usesTwoRows   bool     // Cache the layout decision to prevent flickering
+	renderedLines []string              // Rendered text cache
+	metrics       ansifonts.TextMetrics // Layout of renderedLines, measured in kerning mode
+	usesTwoRows   bool                  // Cache the layout decision to prevent flickering
+
+	renderedFont    *ansifonts.Font         // Font renderedLines were rendered with, nil when nothing was rendered
+	renderedOptions ansifonts.RenderOptions // Options renderedLines were rendered with, for animated exports
 }
 
 // model is the main application model composed of sub-models
@@ -6,6 +6,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/paulilaaso/bit/ansifonts"
 )
 
 // truncateText truncates text to fit within maxWidth, adding "..." if needed
@@ -107,6 +108,15 @@ func (m *model) createPanelContents(contentWidth int) (string, string, string, s
 		// When in text alignment mode, show current alignment
 		alignmentNames := []string{"Left", "Center", "Right"}
 		textPanelContent = truncateText(alignmentNames[int(m.textInput.alignment)], contentWidth)
+	} else if m.uiState.focusedPanel == TextInputPanel && m.textInput.mode == TextRowStyleMode {
+		// When in row style mode, show the current row's preset and pinned font
+		style := m.textInput.rowStyles[m.textInput.currentRow]
+		option := rowStyleOptions[style.option]
+		content := option.Name
+		if option.PinFont && style.fontIndex < len(m.font.fonts) {
+			content = fmt.Sprintf("%s: %s", content, m.font.fonts[style.fontIndex].Name)
+		}
+		textPanelContent = truncateText(content, contentWidth)
 	} else {
 		// When not in edit mode, show row count and preview
 		nonEmptyRows := countNonEmptyRows(m.textInput.textRows)
@@ -153,7 +163,11 @@ func (m *model) createPanelContents(contentWidth int) (string, string, string, s
 
 	// Color content based on current sub-mode
 	var colorContent string
-	if m.color.subMode == TextColorMode {
+	if m.color.subMode == TextColorMode && m.color.paletteMode == ansifonts.PalettePerChar {
+		colorContent = truncateText("Random Letters", contentWidth)
+	} else if m.color.subMode == TextColorMode && m.color.paletteMode == ansifonts.PalettePerWord {
+		colorContent = truncateText("Random Words", contentWidth)
+	} else if m.color.subMode == TextColorMode {
 		colorContent = truncateText(colorOptions[m.color.textColor].Name, contentWidth)
 	} else if m.color.subMode == GradientColorMode {
 		if m.color.gradientEnabled {
@@ -161,22 +175,39 @@ func (m *model) createPanelContents(contentWidth int) (string, string, string, s
 		} else {
 			colorContent = truncateText("None", contentWidth)
 		}
+	} else if m.color.subMode == PatternMode {
+		colorContent = truncateText(patternOptions[m.color.patternIndex].Name, contentWidth)
 	} else { // Gradient direction mode
 		colorContent = truncateText(gradientDirectionOptions[int(m.color.gradientDirection)].Name, contentWidth)
 	}
 
 	var scaleContent string
-	switch m.scale.scale {
-	case ScaleHalf:
-		scaleContent = truncateText("0.5x", contentWidth)
-	case ScaleOne:
-		scaleContent = truncateText("1x", contentWidth)
-	case ScaleTwo:
-		scaleContent = truncateText("2x", contentWidth)
-	case ScaleFour:
-		scaleContent = truncateText("4x", contentWidth)
+	switch m.scale.subMode {
+	case SyntheticBoldMode:
+		if m.scale.boldStrength == 0 {
+			scaleContent = truncateText("Off", contentWidth)
+		} else if m.scale.boldStrength == 1 {
+			scaleContent = truncateText("1 pixel", contentWidth)
+		} else {
+			scaleContent = truncateText(fmt.Sprintf("%d pixels", m.scale.boldStrength), contentWidth)
+		}
+	case SyntheticObliqueMode:
+		scaleContent = truncateText(obliqueOptions[m.scale.obliqueIndex].Name, contentWidth)
 	default:
-		scaleContent = truncateText("1x", contentWidth)
+		switch m.scale.scale {
+		case ScaleHalf:
+			scaleContent = truncateText("0.5x", contentWidth)
+		case ScaleOne:
+			scaleContent = truncateText("1x", contentWidth)
+		case ScaleTwo:
+			scaleContent = truncateText("2x", contentWidth)
+		case ScaleFour:
+			scaleContent = truncateText("4x", contentWidth)
+		case ScaleFit:
+			scaleContent = truncateText("Fit", contentWidth)
+		default:
+			scaleContent = truncateText("1x", contentWidth)
+		}
 	}
 
 	// Combined shadow content based on current sub-mode
@@ -185,6 +216,12 @@ func (m *model) createPanelContents(contentWidth int) (string, string, string, s
 		shadowContent = truncateText(shadowPixelOptions[m.shadow.horizontalIndex].Name, contentWidth)
 	} else if m.shadow.subMode == VerticalShadowMode {
 		shadowContent = truncateText(verticalShadowPixelOptions[m.shadow.verticalIndex].Name, contentWidth)
+	} else if m.shadow.subMode == GlowMode {
+		shadowContent = truncateText(glowOptions[m.shadow.glowIndex].Name, contentWidth)
+	} else if m.shadow.subMode == ExtrusionMode {
+		shadowContent = truncateText(extrusionOptions[m.shadow.extrusionIndex].Name, contentWidth)
+	} else if m.shadow.subMode == ReflectionMode {
+		shadowContent = truncateText(reflectionOptions[m.shadow.reflectionIndex].Name, contentWidth)
 	} else { // Style mode (ANSI character texture)
 		// Display the actual ANSI character texture instead of just the name
 		styleChar := string(shadowStyleOptions[m.shadow.style].Char)
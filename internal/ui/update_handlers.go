@@ -69,6 +69,21 @@ func (m *model) handleExportModeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.export.showOverwritePrompt {
 		return m.handleOverwritePromptKeys(msg)
 	}
+	if m.export.showPNGOptions {
+		return m.handlePNGOptionsKeys(msg)
+	}
+	if m.export.showGoOptions {
+		return m.handleGoOptionsKeys(msg)
+	}
+	if m.export.showANSOptions {
+		return m.handleANSOptionsKeys(msg)
+	}
+	if m.export.showHTMLOptions {
+		return m.handleHTMLOptionsKeys(msg)
+	}
+	if m.export.showShellOptions {
+		return m.handleShellOptionsKeys(msg)
+	}
 
 	switch msg.String() {
 	case "esc":
@@ -76,6 +91,35 @@ func (m *model) handleExportModeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.export.filenameInput.Blur()
 		return m, nil
 	case "enter":
+		if m.export.filenameInput.Value() != "" && m.export.format == "PNG" {
+			// Choose the image options before exporting
+			m.export.showPNGOptions = true
+			return m, nil
+		}
+		if m.export.filenameInput.Value() != "" && m.export.format == "GO" {
+			// Choose between a main program and a package before exporting
+			m.export.showGoOptions = true
+			m.export.filenameInput.Blur()
+			m.focusGoOption(m.export.goOptionRow)
+			return m, nil
+		}
+		if m.export.filenameInput.Value() != "" && m.export.format == "ANS" {
+			// Enter the SAUCE author before exporting
+			m.export.showANSOptions = true
+			m.export.filenameInput.Blur()
+			m.export.ansAuthorInput.Focus()
+			return m, nil
+		}
+		if m.export.filenameInput.Value() != "" && m.export.format == "HTML" {
+			// Choose between a page and a fragment before exporting
+			m.export.showHTMLOptions = true
+			return m, nil
+		}
+		if m.export.filenameInput.Value() != "" && noColorFallbackFormats[m.export.format] {
+			// Choose whether to add a NO_COLOR fallback before exporting
+			m.export.showShellOptions = true
+			return m, nil
+		}
 		if m.export.filenameInput.Value() != "" {
 			m.exportText()
 			// Don't close export mode yet - let overwrite prompt handle it
@@ -97,6 +141,161 @@ func (m *model) handleExportModeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// handlePNGOptionsKeys handles keyboard input for the PNG options step
+func (m *model) handlePNGOptionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		m.export.pngOptionRow = (m.export.pngOptionRow - 1 + TotalPNGOptionRows) % TotalPNGOptionRows
+	case "down", "j", "tab":
+		m.export.pngOptionRow = (m.export.pngOptionRow + 1) % TotalPNGOptionRows
+	case "left", "h":
+		m.adjustPNGOption(-1)
+	case "right", "l":
+		m.adjustPNGOption(1)
+	case "enter":
+		m.export.showPNGOptions = false
+		m.exportText()
+		// Don't close export mode yet - let overwrite prompt handle it
+		if !m.export.showOverwritePrompt {
+			m.export.active = false
+			m.export.filenameInput.Blur()
+		}
+	case "esc":
+		// Back to the filename
+		m.export.showPNGOptions = false
+	}
+	return m, nil
+}
+
+// adjustPNGOption cycles the value of the focused PNG option
+func (m *model) adjustPNGOption(direction int) {
+	cycle := func(index, count int) int {
+		return (index + direction + count) % count
+	}
+	switch m.export.pngOptionRow {
+	case PNGCellsRow:
+		m.export.pngCellIndex = cycle(m.export.pngCellIndex, len(pngCellOptions))
+	case PNGScaleRow:
+		m.export.pngScaleIndex = cycle(m.export.pngScaleIndex, len(pngScaleOptions))
+	case PNGBackgroundRow:
+		m.export.pngBackgroundIdx = cycle(m.export.pngBackgroundIdx, len(pngBackgroundOptions))
+	case PNGPaddingRow:
+		m.export.pngPaddingIndex = cycle(m.export.pngPaddingIndex, len(pngPaddingOptions))
+	case PNGShadesRow:
+		m.export.pngDither = !m.export.pngDither
+	}
+}
+
+// handleGoOptionsKeys handles keyboard input for the Go options step
+func (m *model) handleGoOptionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "up", "shift+tab":
+		m.focusGoOption((m.export.goOptionRow - 1 + TotalGoOptionRows) % TotalGoOptionRows)
+	case "down", "tab":
+		m.focusGoOption((m.export.goOptionRow + 1) % TotalGoOptionRows)
+	case "enter":
+		m.export.showGoOptions = false
+		m.export.goPackageInput.Blur()
+		m.export.goNameInput.Blur()
+		m.exportText()
+		// Don't close export mode yet - let overwrite prompt handle it
+		if !m.export.showOverwritePrompt {
+			m.export.active = false
+		}
+	case "esc":
+		// Back to the filename
+		m.export.showGoOptions = false
+		m.export.goPackageInput.Blur()
+		m.export.goNameInput.Blur()
+		m.export.filenameInput.Focus()
+	default:
+		if m.export.goOptionRow == GoPackageRow {
+			m.export.goPackageInput, cmd = m.export.goPackageInput.Update(msg)
+		} else {
+			m.export.goNameInput, cmd = m.export.goNameInput.Update(msg)
+		}
+	}
+	return m, cmd
+}
+
+// handleANSOptionsKeys handles keyboard input for the ANS options step
+func (m *model) handleANSOptionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "enter":
+		m.export.showANSOptions = false
+		m.export.ansAuthorInput.Blur()
+		m.exportText()
+		// Don't close export mode yet - let overwrite prompt handle it
+		if !m.export.showOverwritePrompt {
+			m.export.active = false
+		}
+	case "esc":
+		// Back to the filename
+		m.export.showANSOptions = false
+		m.export.ansAuthorInput.Blur()
+		m.export.filenameInput.Focus()
+	default:
+		m.export.ansAuthorInput, cmd = m.export.ansAuthorInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// handleHTMLOptionsKeys handles keyboard input for the HTML options step
+func (m *model) handleHTMLOptionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "left", "right", "h", "l", " ":
+		m.export.htmlFragment = !m.export.htmlFragment
+	case "enter":
+		m.export.showHTMLOptions = false
+		m.exportText()
+		// Don't close export mode yet - let overwrite prompt handle it
+		if !m.export.showOverwritePrompt {
+			m.export.active = false
+			m.export.filenameInput.Blur()
+		}
+	case "esc":
+		// Back to the filename
+		m.export.showHTMLOptions = false
+	}
+	return m, nil
+}
+
+// handleShellOptionsKeys handles keyboard input for the NO_COLOR step
+func (m *model) handleShellOptionsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "left", "right", "h", "l", " ":
+		m.export.noColorFallback = !m.export.noColorFallback
+	case "enter":
+		m.export.showShellOptions = false
+		m.exportText()
+		// Don't close export mode yet - let overwrite prompt handle it
+		if !m.export.showOverwritePrompt {
+			m.export.active = false
+			m.export.filenameInput.Blur()
+		}
+	case "esc":
+		// Back to the filename
+		m.export.showShellOptions = false
+	}
+	return m, nil
+}
+
+// focusGoOption focuses the input of a row of the Go options step
+func (m *model) focusGoOption(row GoOptionRow) {
+	m.export.goOptionRow = row
+	if row == GoPackageRow {
+		m.export.goPackageInput.Focus()
+		m.export.goNameInput.Blur()
+	} else {
+		m.export.goPackageInput.Blur()
+		m.export.goNameInput.Focus()
+	}
+}
+
 // handleOverwritePromptKeys handles keyboard input for the overwrite confirmation prompt
 func (m *model) handleOverwritePromptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
@@ -170,6 +369,7 @@ func (m *model) handleTextPanelUpdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		} else {
 			m.textInput.input.Blur()
 		}
+		m.renderText()
 	case "up", "down", "k", "j":
 		if m.textInput.mode == TextEntryMode && m.textInput.input.Focused() {
 			if msg.String() == "up" || msg.String() == "down" {
@@ -187,10 +387,14 @@ func (m *model) handleTextPanelUpdate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		} else if m.textInput.mode == TextAlignmentMode {
 			m.handleTextAlignment(msg.String())
+		} else if m.textInput.mode == TextRowStyleMode {
+			m.handleRowStyle(msg.String())
 		}
 	case "enter":
 		if m.textInput.mode == TextEntryMode || m.textInput.mode == TextKerningMode {
 			m.handleTextInputToggle()
+		} else if m.textInput.mode == TextRowStyleMode {
+			m.selectNextRow()
 		}
 	case "e", "r":
 		// Check if text input is focused before handling special keys
@@ -376,6 +580,44 @@ func (m *model) handleTextAlignment(direction string) {
 	m.renderText()
 }
 
+// handleRowStyle cycles the style preset of the current row
+func (m *model) handleRowStyle(direction string) {
+	row := m.textInput.currentRow
+	style := m.textInput.rowStyles[row]
+	previous := rowStyleOptions[style.option]
+	if isUpKey(direction) {
+		style.option = (style.option - 1 + len(rowStyleOptions)) % len(rowStyleOptions)
+	} else {
+		style.option = (style.option + 1) % len(rowStyleOptions)
+	}
+	if rowStyleOptions[style.option].PinFont && !previous.PinFont {
+		// Pin the font that is selected now; later font changes leave this row alone
+		style.fontIndex = m.font.selectedFont
+	}
+
+	if m.textInput.rowStyles == nil {
+		m.textInput.rowStyles = make(map[int]rowStyle)
+	}
+	if style.option == 0 {
+		delete(m.textInput.rowStyles, row)
+	} else {
+		m.textInput.rowStyles[row] = style
+	}
+	m.renderText()
+}
+
+// selectNextRow moves the row style selection to the next text row
+func (m *model) selectNextRow() {
+	if len(m.textInput.textRows) == 0 {
+		return
+	}
+	m.textInput.currentRow = (m.textInput.currentRow + 1) % len(m.textInput.textRows)
+	m.textInput.input.SetValue(m.textInput.textRows[m.textInput.currentRow])
+	if m.textInput.currentRow < len(m.textInput.rowCursors) {
+		m.textInput.input.SetCursor(m.textInput.rowCursors[m.textInput.currentRow])
+	}
+}
+
 // handleTextInputToggle toggles text input focus
 func (m *model) handleTextInputToggle() {
 	if m.textInput.input.Focused() {
@@ -509,9 +751,11 @@ func (m *model) handleColorPanelUpdate(msg tea.KeyMsg) {
 		switch m.color.subMode {
 		case TextColorMode:
 			m.color.textColor = (m.color.textColor + direction + len(colorOptions)) % len(colorOptions)
+			m.color.paletteMode = ansifonts.NoPalette
 		case GradientColorMode:
 			m.color.gradientColor = (m.color.gradientColor + direction + len(colorOptions)) % len(colorOptions)
 			m.color.gradientEnabled = (m.color.gradientColor != m.color.textColor)
+			m.color.paletteMode = ansifonts.NoPalette
 		case GradientDirectionMode:
 			// Ensure we don't get negative indices even with unusual values
 			newIndex := (int(m.color.gradientDirection) + direction) % int(TotalGradientDirections)
@@ -520,6 +764,8 @@ func (m *model) handleColorPanelUpdate(msg tea.KeyMsg) {
 				newIndex += int(TotalGradientDirections)
 			}
 			m.color.gradientDirection = GradientDirection(newIndex)
+		case PatternMode:
+			m.color.patternIndex = (m.color.patternIndex + direction + len(patternOptions)) % len(patternOptions)
 		}
 		m.renderText()
 	}
@@ -527,16 +773,45 @@ func (m *model) handleColorPanelUpdate(msg tea.KeyMsg) {
 
 // handleScalePanelUpdate handles updates for the scale panel
 func (m *model) handleScalePanelUpdate(msg tea.KeyMsg) {
-	switch {
-	case isUpKey(msg.String()):
-		if m.scale.scale < MaxScale {
-			m.scale.scale++
-			m.renderText()
+	switch m.scale.subMode {
+	case TextScaleMode:
+		switch {
+		case isUpKey(msg.String()):
+			if m.scale.scale < MaxScale {
+				m.scale.scale++
+				m.renderText()
+			}
+		case isDownKey(msg.String()):
+			if m.scale.scale > MinScale {
+				m.scale.scale--
+				m.renderText()
+			}
 		}
-	case isDownKey(msg.String()):
-		if m.scale.scale > MinScale {
-			m.scale.scale--
-			m.renderText()
+	case SyntheticBoldMode:
+		switch {
+		case isUpKey(msg.String()):
+			if m.scale.boldStrength < MaxBoldStrength {
+				m.scale.boldStrength++
+				m.renderText()
+			}
+		case isDownKey(msg.String()):
+			if m.scale.boldStrength > MinBoldStrength {
+				m.scale.boldStrength--
+				m.renderText()
+			}
+		}
+	case SyntheticObliqueMode:
+		switch {
+		case isUpKey(msg.String()):
+			if m.scale.obliqueIndex < len(obliqueOptions)-1 {
+				m.scale.obliqueIndex++
+				m.renderText()
+			}
+		case isDownKey(msg.String()):
+			if m.scale.obliqueIndex > 0 {
+				m.scale.obliqueIndex--
+				m.renderText()
+			}
 		}
 	}
 
@@ -572,11 +847,21 @@ func (m *model) updateShadowWarning() {
 	}
 
 	// Check for half-pixel usage, which affects shadow compatibility
-	hasHalfPixels := ansifonts.DetectHalfPixelUsage(m.textInput.currentText, ansiFontData, m.getScaleFactorFloat())
+	hasHalfPixels := ansifonts.DetectHalfPixelUsageWithOptions(m.textInput.currentText, ansiFontData, ansifonts.RenderOptions{
+		ScaleFactor:  m.getScaleFactorFloat(),
+		ObliqueShear: obliqueOptions[m.scale.obliqueIndex].Shear,
+	})
 
 	// Update warning based on actual half-pixel condition and shadow settings
 	// Use canonical offset values instead of UI indices
-	m.shadow.showWarning = hasHalfPixels && m.shadow.enabled && (m.shadow.horizontalOffset != 0 || m.shadow.verticalOffset != 0)
+	m.shadow.showWarning = hasHalfPixels && m.hasShadowLayers()
+}
+
+// hasShadowLayers reports whether a shadow or extrusion would be drawn, which
+// half-pixel text cannot support
+func (m *model) hasShadowLayers() bool {
+	return (m.shadow.enabled || extrusionOptions[m.shadow.extrusionIndex].Enabled) &&
+		(m.shadow.horizontalOffset != 0 || m.shadow.verticalOffset != 0)
 }
 
 // handleShadowPanelUpdate handles updates for the shadow panel
@@ -592,6 +877,12 @@ func (m *model) handleShadowPanelUpdate(msg tea.KeyMsg) {
 			m.handleVerticalShadow(msg.String())
 		case ShadowStyleMode:
 			m.handleShadowStyle(msg.String())
+		case GlowMode:
+			m.handleGlow(msg.String())
+		case ExtrusionMode:
+			m.handleExtrusion(msg.String())
+		case ReflectionMode:
+			m.handleReflection(msg.String())
 		}
 		m.renderText()
 
@@ -635,6 +926,45 @@ func (m *model) handleShadowStyle(direction string) {
 	}
 }
 
+// handleGlow handles glow preset changes
+func (m *model) handleGlow(direction string) {
+	if isUpKey(direction) {
+		if m.shadow.glowIndex < len(glowOptions)-1 {
+			m.shadow.glowIndex++
+		}
+	} else {
+		if m.shadow.glowIndex > 0 {
+			m.shadow.glowIndex--
+		}
+	}
+}
+
+// handleExtrusion handles extrusion preset changes
+func (m *model) handleExtrusion(direction string) {
+	if isUpKey(direction) {
+		if m.shadow.extrusionIndex < len(extrusionOptions)-1 {
+			m.shadow.extrusionIndex++
+		}
+	} else {
+		if m.shadow.extrusionIndex > 0 {
+			m.shadow.extrusionIndex--
+		}
+	}
+}
+
+// handleReflection handles reflection preset changes
+func (m *model) handleReflection(direction string) {
+	if isUpKey(direction) {
+		if m.shadow.reflectionIndex < len(reflectionOptions)-1 {
+			m.shadow.reflectionIndex++
+		}
+	} else {
+		if m.shadow.reflectionIndex > 0 {
+			m.shadow.reflectionIndex--
+		}
+	}
+}
+
 // handleRandomize randomizes font and color settings
 func (m *model) handleRandomize() {
 	m.font.selectedFont = rand.IntN(len(m.font.fonts))
@@ -653,6 +983,10 @@ func (m *model) handleRandomize() {
 	m.color.gradientEnabled = (m.color.gradientColor != m.color.textColor)
 	m.color.gradientDirection = GradientDirection(rand.IntN(int(TotalGradientDirections)))
 
+	// Pick between the gradient and random colors per letter or per word
+	m.color.paletteMode = ansifonts.PaletteMode(rand.IntN(int(ansifonts.PalettePerWord) + 1))
+	m.color.paletteSeed = rand.Uint64()
+
 	// Update shadow warning after randomization
 	m.updateShadowWarning()
 	m.renderText()
@@ -9,7 +9,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/paulilaaso/bit/internal/export"
+	"github.com/paulilaaso/bit/export"
 )
 
 // ansiRegex is compiled once at package level for efficiency
@@ -59,7 +59,7 @@ func (m model) renderControlsView() string {
 
 // renderTextDisplayView renders the main text display area
 func (m model) renderTextDisplayView(mainDisplayHeight int) string {
-	renderedText := strings.Join(m.uiState.renderedLines, "\n")
+	renderedText := strings.Join(m.withKerningMarker(m.uiState.renderedLines), "\n")
 	if renderedText == "" {
 		renderedText = "Enter some text to see the rendered output"
 	}
@@ -76,6 +76,38 @@ func (m model) renderTextDisplayView(mainDisplayHeight int) string {
 	return fixedTextDisplayStyle.Render(clippedText)
 }
 
+// withKerningMarker adds a row below the current text row with a marker under
+// the character whose spacing the kerning mode adjusts
+func (m model) withKerningMarker(lines []string) []string {
+	if m.uiState.focusedPanel != TextInputPanel || m.textInput.mode != TextKerningMode {
+		return lines
+	}
+	metricLines := m.uiState.metrics.Lines
+	if m.textInput.currentRow >= len(metricLines) {
+		return lines
+	}
+	line := metricLines[m.textInput.currentRow]
+	if line.Box.Height == 0 {
+		return lines
+	}
+
+	// Point at the character after the cursor, or past the end of the row
+	x := line.Box.X + line.Box.Width
+	if cursor := m.textInput.input.Position(); cursor < len(line.Chars) {
+		x = int(line.Chars[cursor].X)
+	}
+	row := line.Box.Y + line.Box.Height
+	if x < 0 || row > len(lines) {
+		return lines
+	}
+
+	width := max(m.uiState.metrics.Width, x+1)
+	marker := strings.Repeat(" ", x) + lipgloss.NewStyle().Foreground(lipgloss.Color(ColorGray)).Render("▲") + strings.Repeat(" ", width-x-1)
+	result := append([]string{}, lines[:row]...)
+	result = append(result, marker)
+	return append(result, lines[row:]...)
+}
+
 // renderControlPanelsView renders all control panels
 func (m model) renderControlPanelsView() string {
 	panelWidth, contentWidth, spacerWidth, _, _ := m.calculateLayoutParameters()
@@ -135,6 +167,8 @@ func (m model) createTextInputLabel(labelWidth int) string {
 				labelText = fmt.Sprintf("%s %+d", labelText, kerning)
 			}
 		}
+	} else if m.textInput.mode == TextRowStyleMode {
+		labelText = fmt.Sprintf("Row Style (Row %d/%d)", m.textInput.currentRow+1, len(m.textInput.textRows))
 	} else {
 		labelText = "Text Alignment"
 	}
@@ -193,6 +227,8 @@ func (m model) createColorLabel(labelWidth int) string {
 		labelText = "Text Color 2"
 	case GradientDirectionMode:
 		labelText = "Gradient ↔/↕"
+	case PatternMode:
+		labelText = "Pattern Fill"
 	default:
 		labelText = "Text Color 1"
 	}
@@ -203,7 +239,18 @@ func (m model) createColorLabel(labelWidth int) string {
 // createScaleLabel creates the label for the scale panel
 func (m model) createScaleLabel(labelWidth int) string {
 	labelStyles := createLabelStyles()
-	return labelStyles.Scale.Render(truncateText("Text Scale", labelWidth))
+
+	var labelText string
+	switch m.scale.subMode {
+	case SyntheticBoldMode:
+		labelText = "Synthetic Bold"
+	case SyntheticObliqueMode:
+		labelText = "Synthetic Italic"
+	default:
+		labelText = "Text Scale"
+	}
+
+	return labelStyles.Scale.Render(truncateText(labelText, labelWidth))
 }
 
 // createShadowLabel creates the label for the shadow panel
@@ -216,6 +263,12 @@ func (m model) createShadowLabel(labelWidth int) string {
 		labelText = "Shadow ↕"
 	case ShadowStyleMode:
 		labelText = "Shadow Style"
+	case GlowMode:
+		labelText = "Glow"
+	case ExtrusionMode:
+		labelText = "Extrusion"
+	case ReflectionMode:
+		labelText = "Reflection"
 	default:
 		labelText = "Shadow ↔"
 	}
@@ -232,6 +285,21 @@ func (m model) renderExportView() string {
 	if m.export.showOverwritePrompt {
 		return m.renderOverwritePrompt()
 	}
+	if m.export.showPNGOptions {
+		return m.renderPNGOptions()
+	}
+	if m.export.showGoOptions {
+		return m.renderGoOptions()
+	}
+	if m.export.showANSOptions {
+		return m.renderANSOptions()
+	}
+	if m.export.showHTMLOptions {
+		return m.renderHTMLOptions()
+	}
+	if m.export.showShellOptions {
+		return m.renderShellOptions()
+	}
 
 	title := titleStyle.Render(fmt.Sprintf("Export ANSI as %s", m.getFormatDescription(m.export.format)))
 
@@ -253,20 +321,22 @@ func (m model) renderExportView() string {
 		Foreground(lipgloss.Color(ColorFaint)).
 		Padding(0, 1)
 
-	// Get format names from export manager
-	formatNames := m.export.manager.GetFormatNames()
-
-	for i, format := range formatNames {
+	// Get format names from export manager, wrapping them into rows that fit the screen
+	var formatRows []string
+	for _, format := range m.export.manager.GetFormatNames() {
+		option := normalFormatStyle.Render(format)
 		if format == m.export.format {
-			formatOptions = append(formatOptions, selectedFormatStyle.Render(format))
-		} else {
-			formatOptions = append(formatOptions, normalFormatStyle.Render(format))
+			option = selectedFormatStyle.Render(format)
 		}
-		if i < len(formatNames)-1 {
-			formatOptions = append(formatOptions, "  ")
+		row := strings.Join(formatOptions, "  ")
+		if len(formatOptions) > 0 && m.uiState.width > 0 && lipgloss.Width(row)+2+lipgloss.Width(option) > m.uiState.width-4 {
+			formatRows = append(formatRows, row)
+			formatOptions = nil
 		}
+		formatOptions = append(formatOptions, option)
 	}
-	formatSelection := strings.Join(formatOptions, "")
+	formatRows = append(formatRows, strings.Join(formatOptions, "  "))
+	formatSelection := lipgloss.JoinVertical(lipgloss.Center, formatRows...)
 
 	filenameLabel := lipgloss.NewStyle().
 		Foreground(lipgloss.Color(ColorExport)).
@@ -322,6 +392,248 @@ func (m model) renderExportView() string {
 		Render(exportContent)
 }
 
+// renderPNGOptions renders the PNG options step of the export view
+func (m model) renderPNGOptions() string {
+	title := titleStyle.Render("PNG Export Options")
+
+	shades := "Solid"
+	if m.export.pngDither {
+		shades = "Dither"
+	}
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"Cells", pngCellOptions[m.export.pngCellIndex].Name},
+		{"Scale", fmt.Sprintf("%dx", pngScaleOptions[m.export.pngScaleIndex])},
+		{"Background", pngBackgroundOptions[m.export.pngBackgroundIdx].Name},
+		{"Padding", fmt.Sprintf("%d px", pngPaddingOptions[m.export.pngPaddingIndex])},
+		{"Shades", shades},
+	}
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorExport)).
+		Bold(true).
+		Width(12)
+
+	selectedValueStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorExport)).
+		Foreground(lipgloss.Color(ColorWhite)).
+		Bold(true).
+		Padding(0, 1).
+		Width(20)
+
+	normalValueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorFaint)).
+		Padding(0, 1).
+		Width(20)
+
+	var lines []string
+	for i, row := range rows {
+		value := normalValueStyle.Render("  " + row.value)
+		if PNGOptionRow(i) == m.export.pngOptionRow {
+			value = selectedValueStyle.Render("← " + row.value + " →")
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Center, labelStyle.Render(row.label+":"), value))
+	}
+
+	instructions := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorFaint)).
+		Render("↑↓: Select option • ←→: Change • Enter: Export • Esc: Back")
+
+	optionsContent := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		"",
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+		"",
+		instructions,
+	)
+
+	return lipgloss.NewStyle().
+		Width(m.uiState.width).
+		Height(m.uiState.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(optionsContent)
+}
+
+// renderGoOptions renders the Go options step of the export view
+func (m model) renderGoOptions() string {
+	title := titleStyle.Render("Go Export Options")
+
+	rows := []struct {
+		label string
+		input string
+	}{
+		{"Package", m.export.goPackageInput.View()},
+		{"Identifier", m.export.goNameInput.View()},
+	}
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorExport)).
+		Bold(true).
+		Width(12)
+
+	var lines []string
+	for _, row := range rows {
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, labelStyle.Render(row.label+":"), row.input))
+	}
+
+	hint := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorPalette["Shadow"])).
+		Render("Leave the package empty for a main program; a package gets a Print(w) function")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorFaint)).
+		Render("↑↓: Select field • Enter: Export • Esc: Back")
+
+	optionsContent := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		"",
+		lipgloss.JoinVertical(lipgloss.Left, lines...),
+		"",
+		hint,
+		"",
+		instructions,
+	)
+
+	return lipgloss.NewStyle().
+		Width(m.uiState.width).
+		Height(m.uiState.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(optionsContent)
+}
+
+// renderANSOptions renders the ANS options step of the export view
+func (m model) renderANSOptions() string {
+	title := titleStyle.Render("ANS Export Options")
+
+	label := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorExport)).
+		Bold(true).
+		Width(12).
+		Render("Author:")
+
+	hint := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorPalette["Shadow"])).
+		Render("Recorded in the SAUCE record; leave empty for the font's author")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorFaint)).
+		Render("Enter: Export • Esc: Back")
+
+	optionsContent := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Top, label, m.export.ansAuthorInput.View()),
+		"",
+		hint,
+		"",
+		instructions,
+	)
+
+	return lipgloss.NewStyle().
+		Width(m.uiState.width).
+		Height(m.uiState.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(optionsContent)
+}
+
+// renderHTMLOptions renders the HTML options step of the export view
+func (m model) renderHTMLOptions() string {
+	title := titleStyle.Render("HTML Export Options")
+
+	output := "Page"
+	if m.export.htmlFragment {
+		output = "Fragment"
+	}
+
+	label := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorExport)).
+		Bold(true).
+		Width(12).
+		Render("Output:")
+
+	value := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorExport)).
+		Foreground(lipgloss.Color(ColorWhite)).
+		Bold(true).
+		Padding(0, 1).
+		Width(16).
+		Render("← " + output + " →")
+
+	hint := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorPalette["Shadow"])).
+		Render("A fragment is only the <pre> block, for embedding in another page")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorFaint)).
+		Render("←→: Change • Enter: Export • Esc: Back")
+
+	optionsContent := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Center, label, value),
+		"",
+		hint,
+		"",
+		instructions,
+	)
+
+	return lipgloss.NewStyle().
+		Width(m.uiState.width).
+		Height(m.uiState.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(optionsContent)
+}
+
+// renderShellOptions renders the NO_COLOR step of the export view
+func (m model) renderShellOptions() string {
+	title := titleStyle.Render(fmt.Sprintf("%s Options", m.getFormatDescription(m.export.format)))
+
+	fallback := "Off"
+	if m.export.noColorFallback {
+		fallback = "On"
+	}
+
+	label := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorExport)).
+		Bold(true).
+		Width(20).
+		Render("NO_COLOR fallback:")
+
+	value := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorExport)).
+		Foreground(lipgloss.Color(ColorWhite)).
+		Bold(true).
+		Padding(0, 1).
+		Width(12).
+		Render("← " + fallback + " →")
+
+	hint := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorPalette["Shadow"])).
+		Render("When on, the banner is printed without colors if NO_COLOR is set")
+
+	instructions := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(ColorFaint)).
+		Render("←→: Change • Enter: Export • Esc: Back")
+
+	optionsContent := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		"",
+		lipgloss.JoinHorizontal(lipgloss.Center, label, value),
+		"",
+		hint,
+		"",
+		instructions,
+	)
+
+	return lipgloss.NewStyle().
+		Width(m.uiState.width).
+		Height(m.uiState.height).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(optionsContent)
+}
+
 // renderOverwritePrompt renders the overwrite confirmation dialog
 func (m model) renderOverwritePrompt() string {
 	title := lipgloss.NewStyle().